@@ -20,8 +20,30 @@ type Config struct {
 	ServerPort     string   `yaml:"-"`
 	ServerMode     string   `yaml:"-"`
 	CORSOrigins    []string `yaml:"-"`
+	TrustedProxies []string `yaml:"-"` // 受信任的反向代理（IP或CIDR），为空时忽略 X-Forwarded-For，使用连接的远端地址
 	TestWallets    []string `yaml:"-"` // 测试钱包地址列表
 
+	// 文件存储配置
+	StorageType          string `yaml:"-"` // local/s3
+	StorageLocalDir      string `yaml:"-"`
+	S3Endpoint           string `yaml:"-"`
+	S3Region             string `yaml:"-"`
+	S3Bucket             string `yaml:"-"`
+	S3AccessKey          string `yaml:"-"`
+	S3SecretKey          string `yaml:"-"`
+	DownloadURLExpireMin int    `yaml:"-"` // 附件下载链接有效期（分钟）
+
+	// 代码仓库快照配置
+	GitCloneTimeoutSec int  `yaml:"-"` // 克隆仓库超时时间（秒）
+	GitAllowFileURL    bool `yaml:"-"` // 是否允许 file:// 仓库地址（仅用于本地测试）
+
+	// 作品链接健康检查配置
+	LinkCheckIntervalMin int `yaml:"-"` // 检查间隔（分钟）
+	LinkCheckTimeoutSec  int `yaml:"-"` // 单个链接请求超时（秒）
+
+	// 是否允许链接检查和仓库克隆访问内网地址（仅用于本地测试）
+	AllowPrivateNetwork bool `yaml:"-"`
+
 	// YAML配置结构
 	Database struct {
 		Host     string `yaml:"host"`
@@ -35,12 +57,36 @@ type Config struct {
 		ExpireHours int    `yaml:"expire_hours"`
 	} `yaml:"jwt"`
 	Server struct {
-		Port string `yaml:"port"`
-		Mode string `yaml:"mode"`
+		Port           string   `yaml:"port"`
+		Mode           string   `yaml:"mode"`
+		TrustedProxies []string `yaml:"trusted_proxies"`
 	} `yaml:"server"`
 	CORS struct {
 		AllowOrigins []string `yaml:"allow_origins"`
 	} `yaml:"cors"`
+	Storage struct {
+		Type              string `yaml:"type"`
+		LocalDir          string `yaml:"local_dir"`
+		DownloadExpireMin int    `yaml:"download_expire_minutes"`
+		S3                struct {
+			Endpoint  string `yaml:"endpoint"`
+			Region    string `yaml:"region"`
+			Bucket    string `yaml:"bucket"`
+			AccessKey string `yaml:"access_key"`
+			SecretKey string `yaml:"secret_key"`
+		} `yaml:"s3"`
+	} `yaml:"storage"`
+	Git struct {
+		CloneTimeoutSec int  `yaml:"clone_timeout_seconds"`
+		AllowFileURL    bool `yaml:"allow_file_url"`
+	} `yaml:"git"`
+	LinkCheck struct {
+		IntervalMin int `yaml:"interval_minutes"`
+		TimeoutSec  int `yaml:"timeout_seconds"`
+	} `yaml:"link_check"`
+	Network struct {
+		AllowPrivate bool `yaml:"allow_private"`
+	} `yaml:"network"`
 }
 
 var AppConfig *Config
@@ -67,6 +113,13 @@ func LoadConfig() error {
 			"0x4444444444444444444444444444444444444444",
 			"0x5555555555555555555555555555555555555555",
 		},
+		StorageType:          "local",
+		StorageLocalDir:      "uploads",
+		S3Region:             "us-east-1",
+		DownloadURLExpireMin: 30,
+		GitCloneTimeoutSec:   120,
+		LinkCheckIntervalMin: 30,
+		LinkCheckTimeoutSec:  10,
 	}
 
 	// 尝试从YAML配置文件加载
@@ -97,7 +150,25 @@ func LoadConfig() error {
 		ServerPort:     getEnv("SERVER_PORT", defaultConfig.ServerPort),
 		ServerMode:     getEnv("SERVER_MODE", defaultConfig.ServerMode),
 		CORSOrigins:    getEnvAsSlice("CORS_ALLOW_ORIGINS", defaultConfig.CORSOrigins),
+		TrustedProxies: getEnvAsSlice("TRUSTED_PROXIES", defaultConfig.TrustedProxies),
 		TestWallets:    testWallets,
+
+		StorageType:          getEnv("STORAGE_TYPE", defaultConfig.StorageType),
+		StorageLocalDir:      getEnv("STORAGE_LOCAL_DIR", defaultConfig.StorageLocalDir),
+		S3Endpoint:           getEnv("S3_ENDPOINT", defaultConfig.S3Endpoint),
+		S3Region:             getEnv("S3_REGION", defaultConfig.S3Region),
+		S3Bucket:             getEnv("S3_BUCKET", defaultConfig.S3Bucket),
+		S3AccessKey:          getEnv("S3_ACCESS_KEY", defaultConfig.S3AccessKey),
+		S3SecretKey:          getEnv("S3_SECRET_KEY", defaultConfig.S3SecretKey),
+		DownloadURLExpireMin: getEnvAsInt("DOWNLOAD_URL_EXPIRE_MINUTES", defaultConfig.DownloadURLExpireMin),
+
+		GitCloneTimeoutSec: getEnvAsInt("GIT_CLONE_TIMEOUT_SECONDS", defaultConfig.GitCloneTimeoutSec),
+		GitAllowFileURL:    getEnvAsBool("GIT_ALLOW_FILE_URL", defaultConfig.GitAllowFileURL),
+
+		LinkCheckIntervalMin: getEnvAsInt("LINK_CHECK_INTERVAL_MINUTES", defaultConfig.LinkCheckIntervalMin),
+		LinkCheckTimeoutSec:  getEnvAsInt("LINK_CHECK_TIMEOUT_SECONDS", defaultConfig.LinkCheckTimeoutSec),
+
+		AllowPrivateNetwork: getEnvAsBool("ALLOW_PRIVATE_NETWORK", defaultConfig.AllowPrivateNetwork),
 	}
 
 	return nil
@@ -143,9 +214,51 @@ func loadFromYAML(filename string, defaultConfig *Config) error {
 	if yamlConfig.Server.Mode != "" {
 		defaultConfig.ServerMode = yamlConfig.Server.Mode
 	}
+	if len(yamlConfig.Server.TrustedProxies) > 0 {
+		defaultConfig.TrustedProxies = yamlConfig.Server.TrustedProxies
+	}
 	if len(yamlConfig.CORS.AllowOrigins) > 0 {
 		defaultConfig.CORSOrigins = yamlConfig.CORS.AllowOrigins
 	}
+	if yamlConfig.Storage.Type != "" {
+		defaultConfig.StorageType = yamlConfig.Storage.Type
+	}
+	if yamlConfig.Storage.LocalDir != "" {
+		defaultConfig.StorageLocalDir = yamlConfig.Storage.LocalDir
+	}
+	if yamlConfig.Storage.DownloadExpireMin > 0 {
+		defaultConfig.DownloadURLExpireMin = yamlConfig.Storage.DownloadExpireMin
+	}
+	if yamlConfig.Storage.S3.Endpoint != "" {
+		defaultConfig.S3Endpoint = yamlConfig.Storage.S3.Endpoint
+	}
+	if yamlConfig.Storage.S3.Region != "" {
+		defaultConfig.S3Region = yamlConfig.Storage.S3.Region
+	}
+	if yamlConfig.Storage.S3.Bucket != "" {
+		defaultConfig.S3Bucket = yamlConfig.Storage.S3.Bucket
+	}
+	if yamlConfig.Storage.S3.AccessKey != "" {
+		defaultConfig.S3AccessKey = yamlConfig.Storage.S3.AccessKey
+	}
+	if yamlConfig.Storage.S3.SecretKey != "" {
+		defaultConfig.S3SecretKey = yamlConfig.Storage.S3.SecretKey
+	}
+	if yamlConfig.Git.CloneTimeoutSec > 0 {
+		defaultConfig.GitCloneTimeoutSec = yamlConfig.Git.CloneTimeoutSec
+	}
+	if yamlConfig.Git.AllowFileURL {
+		defaultConfig.GitAllowFileURL = true
+	}
+	if yamlConfig.LinkCheck.IntervalMin > 0 {
+		defaultConfig.LinkCheckIntervalMin = yamlConfig.LinkCheck.IntervalMin
+	}
+	if yamlConfig.LinkCheck.TimeoutSec > 0 {
+		defaultConfig.LinkCheckTimeoutSec = yamlConfig.LinkCheck.TimeoutSec
+	}
+	if yamlConfig.Network.AllowPrivate {
+		defaultConfig.AllowPrivateNetwork = true
+	}
 
 	return nil
 }
@@ -184,6 +297,16 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	switch os.Getenv(key) {
+	case "1", "true", "TRUE", "True":
+		return true
+	case "0", "false", "FALSE", "False":
+		return false
+	}
+	return defaultValue
+}
+
 func getEnvAsSlice(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		// 简单的逗号分隔处理
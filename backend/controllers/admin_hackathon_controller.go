@@ -12,12 +12,16 @@ import (
 )
 
 type AdminHackathonController struct {
-	hackathonService *services.HackathonService
+	hackathonService       *services.HackathonService
+	submissionFieldService *services.SubmissionFieldService
+	voteService            *services.VoteService
 }
 
 func NewAdminHackathonController() *AdminHackathonController {
 	return &AdminHackathonController{
-		hackathonService: &services.HackathonService{},
+		hackathonService:       &services.HackathonService{},
+		submissionFieldService: &services.SubmissionFieldService{},
+		voteService:            &services.VoteService{},
 	}
 }
 
@@ -187,12 +191,15 @@ func (c *AdminHackathonController) SwitchStage(ctx *gin.Context) {
 	userID, _ := ctx.Get("user_id")
 	role, _ := ctx.Get("role")
 
-	if err := c.hackathonService.SwitchStage(id, stage, userID.(uint64), role.(string)); err != nil {
+	underSizeTeams, err := c.hackathonService.SwitchStage(id, stage, userID.(uint64), role.(string))
+	if err != nil {
 		utils.BadRequest(ctx, err.Error())
 		return
 	}
 
-	utils.Success(ctx, nil)
+	utils.Success(ctx, gin.H{
+		"under_size_teams": underSizeTeams,
+	})
 }
 
 // ArchiveHackathon 归档活动（Admin和活动创建者可归档已发布的活动）
@@ -344,6 +351,96 @@ func (c *AdminHackathonController) GetStageTimes(ctx *gin.Context) {
 	utils.Success(ctx, stages)
 }
 
+// UpdateSubmissionFields 更新活动自定义作品字段（仅活动创建者可设置）
+func (c *AdminHackathonController) UpdateSubmissionFields(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	// 获取当前用户信息
+	userID, _ := ctx.Get("user_id")
+	role, _ := ctx.Get("role")
+
+	var req struct {
+		Fields         []models.HackathonSubmissionField `json:"fields"`
+		RequireRepoURL *bool                             `json:"require_repo_url"` // 不传则不修改
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := c.submissionFieldService.UpdateFields(id, req.Fields, req.RequireRepoURL, userID.(uint64), role.(string)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetSubmissionFields 获取活动自定义作品字段
+func (c *AdminHackathonController) GetSubmissionFields(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	fields, err := c.submissionFieldService.GetFields(id)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, fields)
+}
+
+// UpdateVotingRules 更新活动投票规则（仅活动创建者可设置）
+func (c *AdminHackathonController) UpdateVotingRules(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	// 获取当前用户信息
+	userID, _ := ctx.Get("user_id")
+	role, _ := ctx.Get("role")
+
+	var req services.VotingRules
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := c.voteService.UpdateVotingRules(id, req, userID.(uint64), role.(string)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetVotingRules 获取活动投票规则
+func (c *AdminHackathonController) GetVotingRules(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	rules, err := c.voteService.GetVotingRules(id)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, rules)
+}
+
 // GetHackathonStats 获取活动统计信息
 func (c *AdminHackathonController) GetHackathonStats(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hackathon-backend/models"
+	"hackathon-backend/services"
+	"hackathon-backend/utils"
+)
+
+type AdminJudgingController struct {
+	hackathonService  *services.HackathonService
+	judgingService    *services.JudgingService
+	assignmentService *services.JudgeAssignmentService
+}
+
+func NewAdminJudgingController() *AdminJudgingController {
+	return &AdminJudgingController{
+		hackathonService:  &services.HackathonService{},
+		judgingService:    &services.JudgingService{},
+		assignmentService: &services.JudgeAssignmentService{},
+	}
+}
+
+// GetCriteria 获取活动评分标准
+func (c *AdminJudgingController) GetCriteria(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, true)
+	if !ok {
+		return
+	}
+
+	criteria, err := c.judgingService.GetCriteria(hackathonID)
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, criteria)
+}
+
+// UpdateCriteria 更新活动评分标准（仅活动创建者）
+func (c *AdminJudgingController) UpdateCriteria(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	role, _ := ctx.Get("role")
+
+	var req struct {
+		Criteria []models.JudgingCriterion `json:"criteria" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := c.judgingService.UpdateCriteria(id, req.Criteria, userID.(uint64), role.(string)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetJudges 获取活动评委列表
+func (c *AdminJudgingController) GetJudges(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, true)
+	if !ok {
+		return
+	}
+
+	judges, err := c.judgingService.GetJudges(hackathonID)
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, judges)
+}
+
+// AddJudge 添加活动评委（仅活动创建者）
+func (c *AdminJudgingController) AddJudge(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	role, _ := ctx.Get("role")
+
+	var req struct {
+		UserID uint64 `json:"user_id" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	judge, err := c.judgingService.AddJudge(id, req.UserID, userID.(uint64), role.(string))
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, judge)
+}
+
+// RemoveJudge 移除活动评委（仅活动创建者）
+func (c *AdminJudgingController) RemoveJudge(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	judgeUserID, err := strconv.ParseUint(ctx.Param("user_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的用户ID")
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	role, _ := ctx.Get("role")
+
+	if err := c.judgingService.RemoveJudge(id, judgeUserID, userID.(uint64), role.(string)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetScoreSummary 获取活动作品的评委评分汇总（sort=normalized 时按标准化分排序）
+func (c *AdminJudgingController) GetScoreSummary(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, true)
+	if !ok {
+		return
+	}
+
+	summaries, err := c.judgingService.GetScoreSummary(hackathonID, ctx.Query("sort"))
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, summaries)
+}
+
+// GetConflicts 获取评委利益冲突声明
+func (c *AdminJudgingController) GetConflicts(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, true)
+	if !ok {
+		return
+	}
+
+	conflicts, err := c.assignmentService.GetConflicts(hackathonID, 0)
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, conflicts)
+}
+
+// DeclareConflict 代评委声明利益冲突（仅活动创建者）
+func (c *AdminJudgingController) DeclareConflict(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, false)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		JudgeID uint64 `json:"judge_id" binding:"required"`
+		services.JudgeConflictInput
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	conflict, err := c.assignmentService.DeclareConflict(hackathonID, req.JudgeID, req.JudgeConflictInput)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, conflict)
+}
+
+// DeleteConflict 删除评委利益冲突声明（仅活动创建者）
+func (c *AdminJudgingController) DeleteConflict(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, false)
+	if !ok {
+		return
+	}
+
+	conflictID, err := strconv.ParseUint(ctx.Param("conflict_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的冲突声明ID")
+		return
+	}
+
+	if err := c.assignmentService.DeleteConflict(hackathonID, conflictID, 0); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetAssignments 获取评委分配情况
+func (c *AdminJudgingController) GetAssignments(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, true)
+	if !ok {
+		return
+	}
+
+	result, err := c.assignmentService.GetAssignments(hackathonID)
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, result)
+}
+
+// AssignJudges 为作品分配评委（仅活动创建者，reviews_per_submission 为空时使用活动设置）
+func (c *AdminJudgingController) AssignJudges(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, false)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ReviewsPerSubmission int `json:"reviews_per_submission"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	result, err := c.assignmentService.AssignJudges(hackathonID, req.ReviewsPerSubmission)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, result)
+}
+
+// ReassignJudge 评委退出评审，重新分配其未评分的作品（仅活动创建者）
+func (c *AdminJudgingController) ReassignJudge(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, false)
+	if !ok {
+		return
+	}
+
+	judgeUserID, err := strconv.ParseUint(ctx.Param("user_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的用户ID")
+		return
+	}
+
+	result, err := c.assignmentService.ReassignJudge(hackathonID, judgeUserID)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, result)
+}
@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hackathon-backend/services"
+	"hackathon-backend/utils"
+)
+
+type AdminResultController struct {
+	hackathonService   *services.HackathonService
+	resultService      *services.ResultService
+	voteFraudService   *services.VoteFraudService
+	leaderboardService *services.VoteLeaderboardService
+}
+
+func NewAdminResultController() *AdminResultController {
+	return &AdminResultController{
+		hackathonService:   &services.HackathonService{},
+		resultService:      &services.ResultService{},
+		voteFraudService:   &services.VoteFraudService{},
+		leaderboardService: &services.VoteLeaderboardService{},
+	}
+}
+
+// GetScoringRules 获取活动最终得分计算规则
+func (c *AdminResultController) GetScoringRules(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, true)
+	if !ok {
+		return
+	}
+
+	rules, err := c.resultService.GetScoringRules(hackathonID)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, rules)
+}
+
+// UpdateScoringRules 更新活动最终得分计算规则（仅活动创建者）
+func (c *AdminResultController) UpdateScoringRules(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, false)
+	if !ok {
+		return
+	}
+
+	var req services.ScoringRules
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := c.resultService.UpdateScoringRules(hackathonID, req); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetRankings 预览活动当前排名及每个作品的得分明细
+func (c *AdminResultController) GetRankings(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, true)
+	if !ok {
+		return
+	}
+
+	rankings, err := c.resultService.PreviewRankings(hackathonID)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, rankings)
+}
+
+// SetTieBreakPriority 裁定并列作品的先后（仅活动创建者）
+func (c *AdminResultController) SetTieBreakPriority(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, false)
+	if !ok {
+		return
+	}
+
+	submissionID, err := strconv.ParseUint(ctx.Param("submission_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	var req struct {
+		Priority int `json:"priority"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := c.resultService.SetTieBreakPriority(hackathonID, submissionID, req.Priority); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetResultDraft 获取结果草稿
+func (c *AdminResultController) GetResultDraft(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, true)
+	if !ok {
+		return
+	}
+
+	draft, err := c.resultService.GetDraft(hackathonID)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, draft)
+}
+
+// GenerateResultDraft 由结果引擎重新生成结果草稿（仅活动创建者）
+func (c *AdminResultController) GenerateResultDraft(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, false)
+	if !ok {
+		return
+	}
+
+	draft, err := c.resultService.GenerateDraft(hackathonID)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, draft)
+}
+
+// UpdateResultDraftEntry 调整结果草稿中的作品（仅活动创建者）
+func (c *AdminResultController) UpdateResultDraftEntry(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, false)
+	if !ok {
+		return
+	}
+
+	submissionID, err := strconv.ParseUint(ctx.Param("submission_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	var req services.ResultDraftEntryInput
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := c.resultService.UpdateDraftEntry(hackathonID, submissionID, req); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// PublishResults 公布结果草稿（仅活动创建者）
+func (c *AdminResultController) PublishResults(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, false)
+	if !ok {
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+
+	if err := c.resultService.PublishResults(hackathonID, userID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetVoteFraudFlags 获取可疑投票标记
+func (c *AdminResultController) GetVoteFraudFlags(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, true)
+	if !ok {
+		return
+	}
+
+	flags, err := c.voteFraudService.GetFlags(hackathonID, ctx.Query("status"))
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, flags)
+}
+
+// AnalyzeVoteFraud 分析活动投票并标记可疑投票人（仅活动创建者）
+func (c *AdminResultController) AnalyzeVoteFraud(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, false)
+	if !ok {
+		return
+	}
+
+	count, err := c.voteFraudService.AnalyzeHackathon(hackathonID)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, gin.H{"count": count})
+}
+
+// ReviewVoteFraudFlag 处理可疑投票标记，可作废该投票人的投票（仅活动创建者）
+func (c *AdminResultController) ReviewVoteFraudFlag(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, false)
+	if !ok {
+		return
+	}
+
+	flagID, err := strconv.ParseUint(ctx.Param("flag_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的标记ID")
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+
+	if err := c.voteFraudService.ReviewFlag(hackathonID, flagID, userID.(uint64), req.Status); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetVoteLeaderboard 获取实时投票排行榜
+func (c *AdminResultController) GetVoteLeaderboard(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, true)
+	if !ok {
+		return
+	}
+
+	leaderboard, err := c.leaderboardService.GetLeaderboard(hackathonID, true)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, leaderboard)
+}
+
+// StreamVoteLeaderboard 订阅实时投票排行榜（Server-Sent Events，主办方始终可以看到各作品得票数）
+func (c *AdminResultController) StreamVoteLeaderboard(ctx *gin.Context) {
+	hackathonID, ok := checkHackathonAccess(ctx, c.hackathonService, true)
+	if !ok {
+		return
+	}
+
+	streamVoteLeaderboard(ctx, c.leaderboardService, hackathonID, true)
+}
@@ -0,0 +1,308 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hackathon-backend/services"
+	"hackathon-backend/utils"
+)
+
+type AdminSubmissionController struct {
+	hackathonService    *services.HackathonService
+	repoSnapshotService *services.RepoSnapshotService
+	draftService        *services.SubmissionDraftService
+	linkCheckService    *services.LinkCheckService
+	similarityService   *services.SimilarityService
+	moderationService   *services.SubmissionModerationService
+	commentService      *services.SubmissionCommentService
+}
+
+func NewAdminSubmissionController() *AdminSubmissionController {
+	return &AdminSubmissionController{
+		hackathonService:    &services.HackathonService{},
+		repoSnapshotService: &services.RepoSnapshotService{},
+		draftService:        &services.SubmissionDraftService{},
+		linkCheckService:    &services.LinkCheckService{},
+		similarityService:   &services.SimilarityService{},
+		moderationService:   &services.SubmissionModerationService{},
+		commentService:      &services.SubmissionCommentService{},
+	}
+}
+
+// GetSubmissionList 获取活动的全部作品（含草稿和已隐藏、已取消资格的作品）
+func (c *AdminSubmissionController) GetSubmissionList(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, true)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	keyword := ctx.Query("keyword")
+	status := ctx.Query("status")
+	moderationStatus := ctx.Query("moderation_status")
+
+	submissions, total, err := c.moderationService.GetSubmissionList(hackathonID, page, pageSize, keyword, status, moderationStatus)
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(ctx, submissions, page, pageSize, total)
+}
+
+// ModerateSubmission 隐藏、标记或取消作品资格（仅活动创建者）
+func (c *AdminSubmissionController) ModerateSubmission(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, false)
+	if !ok {
+		return
+	}
+
+	submissionID, err := strconv.ParseUint(ctx.Param("submission_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required"`
+		Reason string `json:"reason"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+
+	if err := c.moderationService.ModerateSubmission(hackathonID, submissionID, userID.(uint64), req.Status, req.Reason); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetComments 获取活动的全部作品评论
+func (c *AdminSubmissionController) GetComments(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, true)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+
+	comments, total, err := c.commentService.GetHackathonComments(hackathonID, page, pageSize, ctx.Query("keyword"))
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(ctx, comments, page, pageSize, total)
+}
+
+// DeleteComment 删除作品评论（仅活动创建者）
+func (c *AdminSubmissionController) DeleteComment(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, false)
+	if !ok {
+		return
+	}
+
+	commentID, err := strconv.ParseUint(ctx.Param("comment_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的评论ID")
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+
+	if err := c.commentService.RemoveComment(hackathonID, commentID, userID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetRepoSnapshots 获取作品代码仓库快照结果
+func (c *AdminSubmissionController) GetRepoSnapshots(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, true)
+	if !ok {
+		return
+	}
+
+	submissions, err := c.repoSnapshotService.GetSnapshotList(hackathonID, ctx.Query("status"))
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, submissions)
+}
+
+// RunRepoSnapshots 立即记录活动作品的代码仓库快照（仅活动创建者）
+func (c *AdminSubmissionController) RunRepoSnapshots(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, false)
+	if !ok {
+		return
+	}
+
+	count, err := c.repoSnapshotService.SnapshotHackathon(hackathonID)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, gin.H{"count": count})
+}
+
+// GetDraftSubmissions 获取尚未定稿的作品（临近截止时标记提醒）
+func (c *AdminSubmissionController) GetDraftSubmissions(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, true)
+	if !ok {
+		return
+	}
+
+	result, err := c.draftService.GetDraftSubmissions(hackathonID)
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, result)
+}
+
+// GetBrokenLinks 获取无法访问的作品链接
+func (c *AdminSubmissionController) GetBrokenLinks(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, true)
+	if !ok {
+		return
+	}
+
+	links, err := c.linkCheckService.GetBrokenLinks(hackathonID)
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, links)
+}
+
+// RunLinkChecks 立即检查活动中所有作品的链接（仅活动创建者）
+func (c *AdminSubmissionController) RunLinkChecks(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, false)
+	if !ok {
+		return
+	}
+
+	count, err := c.linkCheckService.CheckHackathon(hackathonID)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, gin.H{"count": count})
+}
+
+// GetSimilarityReports 获取作品相似度报告
+func (c *AdminSubmissionController) GetSimilarityReports(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, true)
+	if !ok {
+		return
+	}
+
+	reports, err := c.similarityService.GetReports(hackathonID, ctx.Query("scope"), ctx.Query("status"))
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, reports)
+}
+
+// AnalyzeSimilarity 分析作品相似度并生成报告（仅活动创建者）
+func (c *AdminSubmissionController) AnalyzeSimilarity(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, false)
+	if !ok {
+		return
+	}
+
+	count, err := c.similarityService.AnalyzeHackathon(hackathonID)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, gin.H{"count": count})
+}
+
+// ReviewSimilarityReport 处理作品相似度报告（仅活动创建者）
+func (c *AdminSubmissionController) ReviewSimilarityReport(ctx *gin.Context) {
+	hackathonID, ok := c.checkHackathonAccess(ctx, false)
+	if !ok {
+		return
+	}
+
+	reportID, err := strconv.ParseUint(ctx.Param("report_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的报告ID")
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+
+	if err := c.similarityService.ReviewReport(hackathonID, reportID, userID.(uint64), req.Status); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// checkHackathonAccess 检查当前用户对活动的访问权限
+func (c *AdminSubmissionController) checkHackathonAccess(ctx *gin.Context, allowAdmin bool) (uint64, bool) {
+	return checkHackathonAccess(ctx, c.hackathonService, allowAdmin)
+}
+
+// checkHackathonAccess 解析活动ID并检查权限：活动创建者可以访问，allowAdmin 为 true 时 Admin 也可以访问
+func checkHackathonAccess(ctx *gin.Context, hackathonService *services.HackathonService, allowAdmin bool) (uint64, bool) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return 0, false
+	}
+
+	userID, _ := ctx.Get("user_id")
+	role, _ := ctx.Get("role")
+
+	if role.(string) == "admin" {
+		if !allowAdmin {
+			utils.Forbidden(ctx, "Admin不能执行此操作")
+			return 0, false
+		}
+		return hackathonID, true
+	}
+
+	isCreator, err := hackathonService.CheckHackathonCreator(hackathonID, userID.(uint64))
+	if err != nil {
+		utils.BadRequest(ctx, "活动不存在")
+		return 0, false
+	}
+	if !isCreator {
+		utils.Forbidden(ctx, "只能管理自己创建的活动")
+		return 0, false
+	}
+	return hackathonID, true
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hackathon-backend/services"
+	"hackathon-backend/utils"
+)
+
+type AdminTeamController struct {
+	teamService *services.TeamService
+}
+
+func NewAdminTeamController() *AdminTeamController {
+	return &AdminTeamController{
+		teamService: &services.TeamService{},
+	}
+}
+
+// LockTeam 主办方强制锁定队伍（仅活动创建者）
+func (c *AdminTeamController) LockTeam(ctx *gin.Context) {
+	c.setTeamStatus(ctx, "locked")
+}
+
+// UnlockTeam 主办方强制解锁队伍（仅活动创建者）
+func (c *AdminTeamController) UnlockTeam(ctx *gin.Context) {
+	c.setTeamStatus(ctx, "recruiting")
+}
+
+func (c *AdminTeamController) setTeamStatus(ctx *gin.Context, status string) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	teamID, err := strconv.ParseUint(ctx.Param("team_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的队伍ID")
+		return
+	}
+
+	// 获取当前用户信息
+	userID, _ := ctx.Get("user_id")
+	role, _ := ctx.Get("role")
+
+	if err := c.teamService.SetTeamStatusByOrganizer(hackathonID, teamID, userID.(uint64), role.(string), status); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// SetTeamSizeOverride 主办方设置队伍人数特批（仅活动创建者）
+func (c *AdminTeamController) SetTeamSizeOverride(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	teamID, err := strconv.ParseUint(ctx.Param("team_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的队伍ID")
+		return
+	}
+
+	var req struct {
+		MinSize *int `json:"min_size"`
+		MaxSize *int `json:"max_size"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	// 获取当前用户信息
+	userID, _ := ctx.Get("user_id")
+	role, _ := ctx.Get("role")
+
+	if err := c.teamService.SetTeamSizeOverride(hackathonID, teamID, userID.(uint64), role.(string), req.MinSize, req.MaxSize); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
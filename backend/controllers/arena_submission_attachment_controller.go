@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hackathon-backend/services"
+	"hackathon-backend/utils"
+)
+
+type ArenaSubmissionAttachmentController struct {
+	attachmentService *services.SubmissionAttachmentService
+}
+
+func NewArenaSubmissionAttachmentController() *ArenaSubmissionAttachmentController {
+	return &ArenaSubmissionAttachmentController{
+		attachmentService: &services.SubmissionAttachmentService{},
+	}
+}
+
+// UploadAttachment 上传作品附件（multipart 表单字段 file）
+func (c *ArenaSubmissionAttachmentController) UploadAttachment(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		utils.BadRequest(ctx, "请选择要上传的文件")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	attachment, err := c.attachmentService.UploadAttachment(id, participantID.(uint64), file)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, attachment)
+}
+
+// GetAttachments 获取作品附件列表
+func (c *ArenaSubmissionAttachmentController) GetAttachments(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	attachments, err := c.attachmentService.ListAttachments(id, participantID.(uint64))
+	if err != nil {
+		utils.NotFound(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, attachments)
+}
+
+// DeleteAttachment 删除作品附件
+func (c *ArenaSubmissionAttachmentController) DeleteAttachment(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	attachmentID, err := strconv.ParseUint(ctx.Param("attachment_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的附件ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	if err := c.attachmentService.DeleteAttachment(id, attachmentID, participantID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// DownloadAttachment 通过签名链接下载附件（无需登录）
+func (c *ArenaSubmissionAttachmentController) DownloadAttachment(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的附件ID")
+		return
+	}
+
+	expires, err := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	if err != nil {
+		utils.Forbidden(ctx, "下载链接无效或已过期")
+		return
+	}
+
+	attachment, reader, err := c.attachmentService.OpenAttachment(id, expires, ctx.Query("signature"))
+	if err != nil {
+		utils.Forbidden(ctx, err.Error())
+		return
+	}
+	defer reader.Close()
+
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	ctx.DataFromReader(http.StatusOK, attachment.Size, contentType, reader, map[string]string{
+		"Content-Disposition": "attachment; filename*=UTF-8''" + url.PathEscape(attachment.FileName),
+	})
+}
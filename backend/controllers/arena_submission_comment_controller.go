@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hackathon-backend/services"
+	"hackathon-backend/utils"
+)
+
+type ArenaSubmissionCommentController struct {
+	commentService *services.SubmissionCommentService
+}
+
+func NewArenaSubmissionCommentController() *ArenaSubmissionCommentController {
+	return &ArenaSubmissionCommentController{
+		commentService: &services.SubmissionCommentService{},
+	}
+}
+
+// CreateComment 发表作品评论或回复
+func (c *ArenaSubmissionCommentController) CreateComment(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	var req struct {
+		ParentID *uint64 `json:"parent_id"`
+		Content  string  `json:"content" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	comment, err := c.commentService.CreateComment(id, participantID.(uint64), req.ParentID, req.Content)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, comment)
+}
+
+// GetComments 获取作品评论列表
+func (c *ArenaSubmissionCommentController) GetComments(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "20"))
+	participantID, _ := ctx.Get("participant_id")
+
+	comments, total, err := c.commentService.GetComments(id, participantID.(uint64), page, pageSize)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(ctx, comments, page, pageSize, total)
+}
+
+// DeleteComment 删除自己的评论
+func (c *ArenaSubmissionCommentController) DeleteComment(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的评论ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	if err := c.commentService.DeleteComment(id, participantID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -12,13 +13,13 @@ import (
 
 type ArenaSubmissionController struct {
 	submissionService *services.SubmissionService
-	teamService       *services.TeamService
+	draftService      *services.SubmissionDraftService
 }
 
 func NewArenaSubmissionController() *ArenaSubmissionController {
 	return &ArenaSubmissionController{
 		submissionService: &services.SubmissionService{},
-		teamService:       &services.TeamService{},
+		draftService:      &services.SubmissionDraftService{},
 	}
 }
 
@@ -72,9 +73,10 @@ func (c *ArenaSubmissionController) GetSubmissionList(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
 	keyword := ctx.Query("keyword")
+	techTag := ctx.Query("tech_tag")
 	sort := ctx.DefaultQuery("sort", "created_at_desc")
 
-	submissions, total, err := c.submissionService.GetSubmissionList(id, page, pageSize, keyword, sort)
+	submissions, total, err := c.submissionService.GetSubmissionList(id, page, pageSize, keyword, techTag, sort)
 	if err != nil {
 		utils.InternalServerError(ctx, err.Error())
 		return
@@ -97,6 +99,13 @@ func (c *ArenaSubmissionController) GetSubmissionByID(ctx *gin.Context) {
 		return
 	}
 
+	// 草稿、已隐藏和已取消资格的作品仅队伍成员可见（队伍可查看审核状态和原因）
+	participantID, _ := ctx.Get("participant_id")
+	if !c.submissionService.CanViewSubmission(submission, participantID.(uint64)) {
+		utils.NotFound(ctx, "作品不存在")
+		return
+	}
+
 	utils.Success(ctx, submission)
 }
 
@@ -165,3 +174,146 @@ func (c *ArenaSubmissionController) GetSubmissionHistory(ctx *gin.Context) {
 	utils.Success(ctx, histories)
 }
 
+
+// GetSubmissionDiff 比较作品的两个版本（from/to 为修改记录ID或 current）
+func (c *ArenaSubmissionController) GetSubmissionDiff(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	from := ctx.Query("from")
+	if from == "" {
+		utils.BadRequest(ctx, "请指定要比较的版本")
+		return
+	}
+	to := ctx.DefaultQuery("to", "current")
+
+	participantID, _ := ctx.Get("participant_id")
+
+	// 查找用户所在的队伍
+	var teamMember models.TeamMember
+	if err := database.DB.Joins("JOIN teams ON team_members.team_id = teams.id").
+		Joins("JOIN submissions ON submissions.team_id = teams.id").
+		Where("team_members.participant_id = ? AND submissions.id = ?", participantID, id).
+		First(&teamMember).Error; err != nil {
+		utils.BadRequest(ctx, "您没有权限查看此作品的修改记录")
+		return
+	}
+
+	diff, err := c.submissionService.DiffSubmissionVersions(id, from, to)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, diff)
+}
+
+// RestoreSubmission 将作品恢复到指定修改记录的版本（仅队长）
+func (c *ArenaSubmissionController) RestoreSubmission(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	var req struct {
+		HistoryID uint64 `json:"history_id" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	// 查找用户所在的队伍
+	var teamMember models.TeamMember
+	if err := database.DB.Joins("JOIN teams ON team_members.team_id = teams.id").
+		Joins("JOIN submissions ON submissions.team_id = teams.id").
+		Where("team_members.participant_id = ? AND submissions.id = ?", participantID, id).
+		First(&teamMember).Error; err != nil {
+		utils.BadRequest(ctx, "您没有权限修改此作品")
+		return
+	}
+
+	// 检查是否是队长
+	if teamMember.Role != "leader" {
+		utils.Forbidden(ctx, "只有队长可以恢复作品版本")
+		return
+	}
+
+	if err := c.submissionService.RestoreSubmissionVersion(id, teamMember.TeamID, req.HistoryID, participantID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetMySubmission 获取我所在队伍的作品（含草稿）及定稿检查清单
+func (c *ArenaSubmissionController) GetMySubmission(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	result, err := c.draftService.GetMySubmission(hackathonID, participantID.(uint64))
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, result)
+}
+
+// FinalizeSubmission 作品定稿（仅队长）
+func (c *ArenaSubmissionController) FinalizeSubmission(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	checklist, err := c.draftService.Finalize(id, participantID.(uint64))
+	if err != nil {
+		if checklist != nil {
+			ctx.JSON(http.StatusBadRequest, utils.Response{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+				Data:    gin.H{"checklist": checklist},
+			})
+			return
+		}
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, gin.H{"checklist": checklist})
+}
+
+// UnfinalizeSubmission 取消定稿（仅队长，提交截止前）
+func (c *ArenaSubmissionController) UnfinalizeSubmission(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	if err := c.draftService.Unfinalize(id, participantID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
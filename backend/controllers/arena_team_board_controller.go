@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hackathon-backend/services"
+	"hackathon-backend/utils"
+)
+
+type ArenaTeamBoardController struct {
+	boardService *services.TeamBoardService
+}
+
+func NewArenaTeamBoardController() *ArenaTeamBoardController {
+	return &ArenaTeamBoardController{
+		boardService: &services.TeamBoardService{},
+	}
+}
+
+// SavePost 发布或更新求组队帖子
+func (c *ArenaTeamBoardController) SavePost(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	var req struct {
+		Pitch  string `json:"pitch" binding:"required"`
+		Skills string `json:"skills"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	post, err := c.boardService.CreateOrUpdatePost(hackathonID, participantID.(uint64), req.Pitch, req.Skills)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, post)
+}
+
+// ClosePost 关闭求组队帖子
+func (c *ArenaTeamBoardController) ClosePost(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	if err := c.boardService.ClosePost(hackathonID, participantID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetMyPost 获取我的求组队帖子
+func (c *ArenaTeamBoardController) GetMyPost(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	post, err := c.boardService.GetMyPost(hackathonID, participantID.(uint64))
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, post)
+}
+
+// GetPostList 获取求组队帖子列表
+func (c *ArenaTeamBoardController) GetPostList(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	keyword := ctx.Query("keyword")
+	skill := ctx.Query("skill")
+
+	posts, total, err := c.boardService.GetPostList(hackathonID, page, pageSize, keyword, skill)
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(ctx, posts, page, pageSize, total)
+}
+
+// SendInvitation 队长邀请求组队的参赛者
+func (c *ArenaTeamBoardController) SendInvitation(ctx *gin.Context) {
+	postID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的帖子ID")
+		return
+	}
+
+	var req struct {
+		Message string `json:"message"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	leaderID, _ := ctx.Get("participant_id")
+
+	invitation, err := c.boardService.SendInvitation(postID, leaderID.(uint64), req.Message)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, invitation)
+}
+
+// GetMyInvitations 获取我收到的队伍邀请
+func (c *ArenaTeamBoardController) GetMyInvitations(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	invitations, err := c.boardService.GetMyInvitations(hackathonID, participantID.(uint64))
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, invitations)
+}
+
+// GetTeamInvitations 获取队伍发出的邀请（仅队长）
+func (c *ArenaTeamBoardController) GetTeamInvitations(ctx *gin.Context) {
+	teamID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的队伍ID")
+		return
+	}
+
+	leaderID, _ := ctx.Get("participant_id")
+
+	invitations, err := c.boardService.GetTeamInvitations(teamID, leaderID.(uint64))
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, invitations)
+}
+
+// AcceptInvitation 接受队伍邀请
+func (c *ArenaTeamBoardController) AcceptInvitation(ctx *gin.Context) {
+	c.respondInvitation(ctx, true)
+}
+
+// DeclineInvitation 拒绝队伍邀请
+func (c *ArenaTeamBoardController) DeclineInvitation(ctx *gin.Context) {
+	c.respondInvitation(ctx, false)
+}
+
+func (c *ArenaTeamBoardController) respondInvitation(ctx *gin.Context, accept bool) {
+	invitationID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的邀请ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	if err := c.boardService.RespondInvitation(invitationID, participantID.(uint64), accept); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
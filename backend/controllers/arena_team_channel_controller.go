@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"hackathon-backend/config"
+	"hackathon-backend/services"
+	"hackathon-backend/utils"
+)
+
+const (
+	// 写超时
+	teamChannelWriteWait = 10 * time.Second
+	// 等待客户端pong的超时时间
+	teamChannelPongWait = 60 * time.Second
+	// 发送ping的间隔，必须小于pongWait
+	teamChannelPingPeriod = teamChannelPongWait * 9 / 10
+	// 客户端单条消息最大字节数
+	teamChannelMaxMessageSize = 8 * 1024
+)
+
+var teamChannelUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		// 与CORS中间件保持一致，仅允许配置中的源
+		for _, allowedOrigin := range config.AppConfig.CORSOrigins {
+			if origin == allowedOrigin {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+type ArenaTeamChannelController struct {
+	channelService *services.TeamChannelService
+}
+
+func NewArenaTeamChannelController() *ArenaTeamChannelController {
+	return &ArenaTeamChannelController{
+		channelService: &services.TeamChannelService{},
+	}
+}
+
+// Connect 连接队伍频道（WebSocket，仅队伍成员）
+func (c *ArenaTeamChannelController) Connect(ctx *gin.Context) {
+	teamID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的队伍ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+	pid := participantID.(uint64)
+
+	if !c.channelService.IsTeamMember(teamID, pid) {
+		utils.Forbidden(ctx, "您不是该队伍成员")
+		return
+	}
+
+	conn, err := teamChannelUpgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// Upgrade 失败时已向客户端返回错误响应
+		return
+	}
+
+	client := c.channelService.Register(teamID, pid)
+
+	// 注册前可能已退出或被移出队伍，此时断开刚建立的连接
+	if !c.channelService.IsTeamMember(teamID, pid) {
+		c.channelService.Unregister(client)
+		conn.Close()
+		return
+	}
+
+	go c.writePump(conn, client)
+	c.readPump(conn, client)
+}
+
+// readPump 读取客户端消息，连接断开时注销
+func (c *ArenaTeamChannelController) readPump(conn *websocket.Conn, client *services.TeamChannelClient) {
+	defer func() {
+		c.channelService.Unregister(client)
+		conn.Close()
+	}()
+
+	conn.SetReadLimit(teamChannelMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(teamChannelPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(teamChannelPongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req struct {
+			Type    string `json:"type"`
+			Content string `json:"content"`
+		}
+		if err := json.Unmarshal(data, &req); err != nil || req.Type != services.TeamChannelEventMessage {
+			c.sendError(client, "无效的消息格式")
+			continue
+		}
+
+		if _, err := c.channelService.SendMessage(client.TeamID, client.ParticipantID, req.Content); err != nil {
+			c.sendError(client, err.Error())
+		}
+	}
+}
+
+// writePump 将频道事件写入连接，并定期发送ping保持连接
+func (c *ArenaTeamChannelController) writePump(conn *websocket.Conn, client *services.TeamChannelClient) {
+	ticker := time.NewTicker(teamChannelPingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case payload, ok := <-client.Send:
+			conn.SetWriteDeadline(time.Now().Add(teamChannelWriteWait))
+			if !ok {
+				// 频道已关闭连接（断开或被移出队伍）
+				conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(teamChannelWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sendError 向当前连接推送错误事件
+func (c *ArenaTeamChannelController) sendError(client *services.TeamChannelClient, message string) {
+	c.channelService.Notify(client, services.TeamChannelEvent{
+		Type: "error",
+		Data: gin.H{"message": message},
+	})
+}
+
+// GetMessages 获取队伍消息记录（仅队伍成员）
+func (c *ArenaTeamChannelController) GetMessages(ctx *gin.Context) {
+	teamID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的队伍ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	if !c.channelService.IsTeamMember(teamID, participantID.(uint64)) {
+		utils.Forbidden(ctx, "您不是该队伍成员")
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "50"))
+
+	messages, total, err := c.channelService.GetMessages(teamID, page, pageSize)
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.SuccessWithPagination(ctx, messages, page, pageSize, total)
+}
+
+// GetOnlineMembers 获取队伍在线成员（仅队伍成员）
+func (c *ArenaTeamChannelController) GetOnlineMembers(ctx *gin.Context) {
+	teamID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的队伍ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	if !c.channelService.IsTeamMember(teamID, participantID.(uint64)) {
+		utils.Forbidden(ctx, "您不是该队伍成员")
+		return
+	}
+
+	utils.Success(ctx, gin.H{
+		"online": c.channelService.OnlineMembers(teamID),
+	})
+}
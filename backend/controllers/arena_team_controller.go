@@ -36,9 +36,7 @@ func (c *ArenaTeamController) CreateTeam(ctx *gin.Context) {
 		return
 	}
 
-	if req.MaxSize == 0 {
-		req.MaxSize = 3 // 默认值
-	}
+	// MaxSize 为0时使用活动设置的最大队伍人数
 
 	participantID, _ := ctx.Get("participant_id")
 
@@ -210,3 +208,39 @@ func (c *ArenaTeamController) UpdateTeam(ctx *gin.Context) {
 	utils.Success(ctx, nil)
 }
 
+
+// LockTeam 锁定队伍
+func (c *ArenaTeamController) LockTeam(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的队伍ID")
+		return
+	}
+
+	leaderID, _ := ctx.Get("participant_id")
+
+	if err := c.teamService.LockTeam(id, leaderID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// UnlockTeam 解锁队伍
+func (c *ArenaTeamController) UnlockTeam(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的队伍ID")
+		return
+	}
+
+	leaderID, _ := ctx.Get("participant_id")
+
+	if err := c.teamService.UnlockTeam(id, leaderID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hackathon-backend/services"
+	"hackathon-backend/utils"
+)
+
+type ArenaTeamMergeController struct {
+	mergeService *services.TeamMergeService
+}
+
+func NewArenaTeamMergeController() *ArenaTeamMergeController {
+	return &ArenaTeamMergeController{
+		mergeService: &services.TeamMergeService{},
+	}
+}
+
+// RequestMerge 发起队伍合并申请（仅队长）
+func (c *ArenaTeamMergeController) RequestMerge(ctx *gin.Context) {
+	teamID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的队伍ID")
+		return
+	}
+
+	var req struct {
+		OtherTeamID     uint64 `json:"other_team_id" binding:"required"`
+		SurvivingTeamID uint64 `json:"surviving_team_id"` // 合并后保留的队伍，默认保留发起方队伍
+		Message         string `json:"message"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	if req.SurvivingTeamID == 0 {
+		req.SurvivingTeamID = teamID
+	}
+
+	leaderID, _ := ctx.Get("participant_id")
+
+	request, err := c.mergeService.RequestMerge(teamID, req.OtherTeamID, req.SurvivingTeamID, leaderID.(uint64), req.Message)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, request)
+}
+
+// GetMergeRequests 获取队伍相关的合并申请（仅队长）
+func (c *ArenaTeamMergeController) GetMergeRequests(ctx *gin.Context) {
+	teamID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的队伍ID")
+		return
+	}
+
+	leaderID, _ := ctx.Get("participant_id")
+
+	requests, err := c.mergeService.GetTeamMergeRequests(teamID, leaderID.(uint64))
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, requests)
+}
+
+// ApproveMerge 同意合并申请
+func (c *ArenaTeamMergeController) ApproveMerge(ctx *gin.Context) {
+	requestID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的申请ID")
+		return
+	}
+
+	leaderID, _ := ctx.Get("participant_id")
+
+	request, err := c.mergeService.ApproveMerge(requestID, leaderID.(uint64))
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, request)
+}
+
+// RejectMerge 拒绝或撤回合并申请
+func (c *ArenaTeamMergeController) RejectMerge(ctx *gin.Context) {
+	requestID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的申请ID")
+		return
+	}
+
+	leaderID, _ := ctx.Get("participant_id")
+
+	if err := c.mergeService.RejectMerge(requestID, leaderID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
@@ -1,7 +1,9 @@
 package controllers
 
 import (
+	"io"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"hackathon-backend/database"
@@ -10,13 +12,20 @@ import (
 	"hackathon-backend/utils"
 )
 
+// 实时排行榜心跳间隔，避免代理因连接空闲而断开
+const voteLeaderboardHeartbeat = 30 * time.Second
+
 type ArenaVoteController struct {
-	voteService *services.VoteService
+	voteService        *services.VoteService
+	ballotService      *services.RankedBallotService
+	leaderboardService *services.VoteLeaderboardService
 }
 
 func NewArenaVoteController() *ArenaVoteController {
 	return &ArenaVoteController{
-		voteService: &services.VoteService{},
+		voteService:        &services.VoteService{},
+		ballotService:      &services.RankedBallotService{},
+		leaderboardService: &services.VoteLeaderboardService{},
 	}
 }
 
@@ -37,7 +46,7 @@ func (c *ArenaVoteController) Vote(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.voteService.Vote(submission.HackathonID, participantID.(uint64), submissionID); err != nil {
+	if err := c.voteService.Vote(submission.HackathonID, participantID.(uint64), submissionID, voteFingerprint(ctx)); err != nil {
 		utils.BadRequest(ctx, err.Error())
 		return
 	}
@@ -63,23 +72,118 @@ func (c *ArenaVoteController) CancelVote(ctx *gin.Context) {
 	utils.Success(ctx, nil)
 }
 
-// GetMyVotes 获取我的投票记录
-func (c *ArenaVoteController) GetMyVotes(ctx *gin.Context) {
+// AllocateVotes 平方投票模式下分配票数（整体替换已有分配）
+func (c *ArenaVoteController) AllocateVotes(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	var req struct {
+		Allocations []services.VoteAllocation `json:"allocations"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	if err := c.voteService.AllocateVotes(hackathonID, participantID.(uint64), req.Allocations, voteFingerprint(ctx)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// SubmitBallot 排序投票模式下提交选票（整体替换已有选票）
+func (c *ArenaVoteController) SubmitBallot(ctx *gin.Context) {
 	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
 		utils.BadRequest(ctx, "无效的活动ID")
 		return
 	}
 
+	var req struct {
+		SubmissionIDs []uint64 `json:"submission_ids" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
 	participantID, _ := ctx.Get("participant_id")
 
-	votes, err := c.voteService.GetMyVotes(hackathonID, participantID.(uint64))
+	ballot, err := c.ballotService.SubmitBallot(hackathonID, participantID.(uint64), req.SubmissionIDs, voteFingerprint(ctx))
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, ballot)
+}
+
+// GetMyBallot 获取我的排序选票
+func (c *ArenaVoteController) GetMyBallot(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	ballot, err := c.ballotService.GetMyBallot(hackathonID, participantID.(uint64))
 	if err != nil {
 		utils.InternalServerError(ctx, err.Error())
 		return
 	}
 
-	utils.Success(ctx, votes)
+	utils.Success(ctx, ballot)
+}
+
+// CancelBallot 撤回排序选票
+func (c *ArenaVoteController) CancelBallot(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	if err := c.ballotService.CancelBallot(hackathonID, participantID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
+
+// GetMyVotes 获取我的投票记录及剩余投票额度
+func (c *ArenaVoteController) GetMyVotes(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	participantID, _ := ctx.Get("participant_id")
+
+	votes, allowance, err := c.voteService.GetMyVotes(hackathonID, participantID.(uint64))
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, gin.H{
+		"votes":     votes,
+		"allowance": allowance,
+	})
 }
 
 // GetResults 获取比赛结果
@@ -98,9 +202,9 @@ func (c *ArenaVoteController) GetResults(ctx *gin.Context) {
 
 	// 计算统计数据
 	var totalVotes, totalTeams, totalSubmissions int64
-	database.DB.Model(&models.Vote{}).Where("hackathon_id = ?", id).Count(&totalVotes)
+	totalVotes = c.voteService.GetHackathonVoteCount(id)
 	database.DB.Model(&models.Team{}).Where("hackathon_id = ? AND deleted_at IS NULL", id).Count(&totalTeams)
-	database.DB.Model(&models.Submission{}).Where("hackathon_id = ? AND draft = 0", id).Count(&totalSubmissions)
+	database.DB.Model(&models.Submission{}).Where("hackathon_id = ? AND draft = 0 AND moderation_status IN ?", id, services.PublicModerationStatuses).Count(&totalSubmissions)
 
 	utils.Success(ctx, gin.H{
 		"rankings": results,
@@ -112,3 +216,89 @@ func (c *ArenaVoteController) GetResults(ctx *gin.Context) {
 	})
 }
 
+// voteFingerprint 获取投票请求的客户端信息
+func voteFingerprint(ctx *gin.Context) services.VoteFingerprint {
+	return services.VoteFingerprint{
+		ClientIP:  ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	}
+}
+
+// GetLeaderboard 获取实时投票排行榜（活动开放公开排行榜时可用，盲投活动在结果公布前只返回总票数）
+func (c *ArenaVoteController) GetLeaderboard(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	tallies, err := c.leaderboardService.CheckPublicAccess(hackathonID)
+	if err != nil {
+		utils.Forbidden(ctx, err.Error())
+		return
+	}
+
+	leaderboard, err := c.leaderboardService.GetLeaderboard(hackathonID, tallies)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, leaderboard)
+}
+
+// StreamLeaderboard 订阅实时投票排行榜（Server-Sent Events）
+func (c *ArenaVoteController) StreamLeaderboard(ctx *gin.Context) {
+	hackathonID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	tallies, err := c.leaderboardService.CheckPublicAccess(hackathonID)
+	if err != nil {
+		utils.Forbidden(ctx, err.Error())
+		return
+	}
+
+	streamVoteLeaderboard(ctx, c.leaderboardService, hackathonID, tallies)
+}
+
+// streamVoteLeaderboard 推送排行榜：连接后先推送当前排行榜，之后在投票变化时推送
+// tallies 为 false 的连接只收到总票数和投票人数（盲投活动结果公布后除外）
+func streamVoteLeaderboard(ctx *gin.Context, leaderboardService *services.VoteLeaderboardService, hackathonID uint64, tallies bool) {
+	leaderboard, err := leaderboardService.GetLeaderboard(hackathonID, tallies)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	client := leaderboardService.Register(hackathonID, tallies)
+	defer leaderboardService.Unregister(client)
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.SSEvent("leaderboard", leaderboard)
+	ctx.Writer.Flush()
+
+	ticker := time.NewTicker(voteLeaderboardHeartbeat)
+	defer ticker.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case payload, ok := <-client.Send:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent("leaderboard", string(payload))
+			return true
+		case <-ticker.C:
+			ctx.SSEvent("ping", time.Now().Unix())
+			return true
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
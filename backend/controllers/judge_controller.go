@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"hackathon-backend/services"
+	"hackathon-backend/utils"
+)
+
+type JudgeController struct {
+	judgingService    *services.JudgingService
+	assignmentService *services.JudgeAssignmentService
+}
+
+func NewJudgeController() *JudgeController {
+	return &JudgeController{
+		judgingService:    &services.JudgingService{},
+		assignmentService: &services.JudgeAssignmentService{},
+	}
+}
+
+// GetMyHackathons 获取评委参与评审的活动
+func (c *JudgeController) GetMyHackathons(ctx *gin.Context) {
+	userID, _ := ctx.Get("user_id")
+
+	hackathons, err := c.judgingService.GetJudgeHackathons(userID.(uint64))
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, hackathons)
+}
+
+// GetCriteria 获取活动评分标准
+func (c *JudgeController) GetCriteria(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	if !c.judgingService.IsJudge(id, userID.(uint64)) {
+		utils.Forbidden(ctx, "您不是该活动的评委")
+		return
+	}
+
+	criteria, err := c.judgingService.GetCriteria(id)
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, criteria)
+}
+
+// GetSubmissions 获取待评审的作品列表及自己的评分
+func (c *JudgeController) GetSubmissions(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+
+	submissions, err := c.judgingService.GetJudgeSubmissions(id, userID.(uint64))
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, submissions)
+}
+
+// GetSubmission 获取作品详情、评分标准及自己的评分
+func (c *JudgeController) GetSubmission(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+
+	result, err := c.judgingService.GetJudgeSubmission(id, userID.(uint64))
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, result)
+}
+
+// SubmitScore 提交或修改作品评分
+func (c *JudgeController) SubmitScore(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的作品ID")
+		return
+	}
+
+	var req struct {
+		Scores  []services.JudgeScoreInput `json:"scores" binding:"required"`
+		Comment string                     `json:"comment"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+
+	score, err := c.judgingService.SubmitScore(id, userID.(uint64), req.Scores, req.Comment)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, score)
+}
+
+// GetMyConflicts 获取自己声明的利益冲突
+func (c *JudgeController) GetMyConflicts(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	if !c.judgingService.IsJudge(id, userID.(uint64)) {
+		utils.Forbidden(ctx, "您不是该活动的评委")
+		return
+	}
+
+	conflicts, err := c.assignmentService.GetConflicts(id, userID.(uint64))
+	if err != nil {
+		utils.InternalServerError(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, conflicts)
+}
+
+// DeclareConflict 声明利益冲突（已分配的冲突作品会被取消分配）
+func (c *JudgeController) DeclareConflict(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	var req services.JudgeConflictInput
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(ctx, "参数错误: "+err.Error())
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+
+	conflict, err := c.assignmentService.DeclareConflict(id, userID.(uint64), req)
+	if err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, conflict)
+}
+
+// DeleteConflict 删除自己声明的利益冲突
+func (c *JudgeController) DeleteConflict(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的活动ID")
+		return
+	}
+
+	conflictID, err := strconv.ParseUint(ctx.Param("conflict_id"), 10, 64)
+	if err != nil {
+		utils.BadRequest(ctx, "无效的冲突声明ID")
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+
+	if err := c.assignmentService.DeleteConflict(id, conflictID, userID.(uint64)); err != nil {
+		utils.BadRequest(ctx, err.Error())
+		return
+	}
+
+	utils.Success(ctx, nil)
+}
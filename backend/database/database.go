@@ -53,14 +53,34 @@ func AutoMigrate() error {
 		&models.Hackathon{},
 		&models.HackathonStage{},
 		&models.HackathonAward{},
+		&models.HackathonSubmissionField{},
 		&models.HackathonPrize{},
 		&models.Registration{},
 		&models.Checkin{},
 		&models.Team{},
 		&models.TeamMember{},
+		&models.TeamMessage{},
+		&models.TeamSeekingPost{},
+		&models.TeamInvitation{},
+		&models.TeamMergeRequest{},
 		&models.Submission{},
 		&models.SubmissionHistory{},
+		&models.SubmissionAttachment{},
+		&models.SubmissionLinkCheck{},
+		&models.SubmissionSimilarityReport{},
+		&models.SubmissionComment{},
 		&models.Vote{},
+		&models.RankedBallot{},
+		&models.RankedBallotItem{},
+		&models.VoteFraudFlag{},
+		&models.HackathonWinner{},
+		&models.HackathonResultEntry{},
+		&models.HackathonJudge{},
+		&models.JudgingCriterion{},
+		&models.JudgeScore{},
+		&models.JudgeScoreItem{},
+		&models.JudgeConflict{},
+		&models.JudgeAssignment{},
 		&models.SponsorApplication{},
 		&models.Sponsor{},
 		&models.HackathonSponsorEvent{},
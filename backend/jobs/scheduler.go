@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"hackathon-backend/services"
+)
+
+// Start 启动后台定时任务
+func Start() {
+	resultService := &services.ResultService{}
+	go runOnce("补录历史比赛结果", resultService.PublishLegacyResults)
+
+	repoSnapshotService := &services.RepoSnapshotService{}
+	go runEvery("仓库快照", time.Minute, repoSnapshotService.RunDueSnapshots)
+
+	draftService := &services.SubmissionDraftService{}
+	go runEvery("未定稿作品提醒", time.Minute, draftService.RunDeadlineReminders)
+
+	linkCheckService := &services.LinkCheckService{}
+	go runEvery("作品链接检查", time.Minute, linkCheckService.RunDueChecks)
+}
+
+// runEvery 按固定间隔执行任务，单次执行出错或 panic 不影响后续执行
+func runEvery(name string, interval time.Duration, job func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		runOnce(name, job)
+	}
+}
+
+func runOnce(name string, job func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("定时任务[%s]异常: %v", name, r)
+		}
+	}()
+
+	if err := job(); err != nil {
+		log.Printf("定时任务[%s]执行失败: %v", name, err)
+	}
+}
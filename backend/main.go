@@ -5,8 +5,10 @@ import (
 
 	"hackathon-backend/config"
 	"hackathon-backend/database"
+	"hackathon-backend/jobs"
 	"hackathon-backend/middleware"
 	"hackathon-backend/routes"
+	"hackathon-backend/storage"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,12 +25,25 @@ func main() {
 	}
 	defer database.CloseDB()
 
+	// 初始化文件存储
+	if err := storage.InitStorage(); err != nil {
+		log.Fatal("Failed to initialize storage:", err)
+	}
+
+	// 启动后台定时任务
+	jobs.Start()
+
 	// 设置Gin模式
 	gin.SetMode(config.AppConfig.ServerMode)
 
 	// 创建Gin引擎
 	router := gin.Default()
 
+	// 只信任配置的反向代理转发的客户端IP，未配置时使用连接的远端地址
+	if err := router.SetTrustedProxies(config.AppConfig.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies:", err)
+	}
+
 	// 添加CORS中间件
 	router.Use(middleware.CORSMiddleware())
 
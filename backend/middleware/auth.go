@@ -11,6 +11,10 @@ import (
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		// 浏览器EventSource无法设置请求头，订阅实时推送时允许通过token查询参数传递
+		if authHeader == "" && strings.Contains(c.GetHeader("Accept"), "text/event-stream") && c.Query("token") != "" {
+			authHeader = "Bearer " + c.Query("token")
+		}
 		if authHeader == "" {
 			utils.Unauthorized(c, "Authorization header is required")
 			c.Abort()
@@ -46,6 +50,10 @@ func AuthMiddleware() gin.HandlerFunc {
 func ParticipantAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		// 浏览器WebSocket无法设置请求头，允许通过token查询参数传递
+		if authHeader == "" && c.IsWebsocket() && c.Query("token") != "" {
+			authHeader = "Bearer " + c.Query("token")
+		}
 		if authHeader == "" {
 			utils.Unauthorized(c, "Authorization header is required")
 			c.Abort()
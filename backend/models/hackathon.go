@@ -8,26 +8,48 @@ import (
 
 // Hackathon 活动表
 type Hackathon struct {
-	ID           uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name         string         `gorm:"type:varchar(100);not null" json:"name"`
-	Description  string         `gorm:"type:text;not null" json:"description"`
-	StartTime    time.Time      `gorm:"not null" json:"start_time"`
-	EndTime      time.Time      `gorm:"not null" json:"end_time"`
-	LocationType string         `gorm:"type:enum('online','offline','hybrid');not null" json:"location_type"`
-	City         string         `gorm:"type:varchar(100)" json:"city"` // 城市
-	LocationDetail string       `gorm:"type:varchar(500)" json:"location_detail"` // 具体地址
-	Status       string         `gorm:"type:enum('preparation','published','registration','checkin','team_formation','submission','voting','results');default:'preparation'" json:"status"`
-	OrganizerID  uint64         `gorm:"index;not null" json:"organizer_id"`
-	MaxTeamSize  int            `gorm:"default:3" json:"max_team_size"`
-	MaxParticipants int         `gorm:"default:0" json:"max_participants"` // 最大参与人数，0表示不限制
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                     uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name                   string         `gorm:"type:varchar(100);not null" json:"name"`
+	Description            string         `gorm:"type:text;not null" json:"description"`
+	StartTime              time.Time      `gorm:"not null" json:"start_time"`
+	EndTime                time.Time      `gorm:"not null" json:"end_time"`
+	LocationType           string         `gorm:"type:enum('online','offline','hybrid');not null" json:"location_type"`
+	City                   string         `gorm:"type:varchar(100)" json:"city"`            // 城市
+	LocationDetail         string         `gorm:"type:varchar(500)" json:"location_detail"` // 具体地址
+	Status                 string         `gorm:"type:enum('preparation','published','registration','checkin','team_formation','submission','voting','results');default:'preparation'" json:"status"`
+	OrganizerID            uint64         `gorm:"index;not null" json:"organizer_id"`
+	MaxTeamSize            int            `gorm:"default:3" json:"max_team_size"`
+	MinTeamSize            int            `gorm:"default:1" json:"min_team_size"`                                                   // 最小队伍人数，锁定队伍时校验
+	MaxAttachmentSizeMB    int            `gorm:"default:50" json:"max_attachment_size_mb"`                                         // 作品附件单个文件大小上限（MB）
+	MaxAttachments         int            `gorm:"default:10" json:"max_attachments"`                                                // 每个作品的附件数量上限
+	AllowedAttachmentTypes string         `gorm:"type:varchar(255)" json:"allowed_attachment_types"`                                // 允许的附件扩展名（逗号分隔），为空使用默认值
+	RequireRepoURL         bool           `json:"require_repo_url"`                                                                 // 作品定稿时是否必须填写代码仓库地址
+	MaxParticipants        int            `gorm:"default:0" json:"max_participants"`                                                // 最大参与人数，0表示不限制
+	ReviewsPerSubmission   int            `gorm:"default:3" json:"reviews_per_submission"`                                          // 每个作品分配的评委人数
+	VotingMode             string         `gorm:"type:enum('approval','quadratic','ranked');default:'approval'" json:"voting_mode"` // 投票模式：approval 普通投票，quadratic 平方投票，ranked 排序投票
+	VoiceCredits           int            `gorm:"default:100" json:"voice_credits"`                                                 // 平方投票模式下每位参赛者的投票额度
+	RankedBallotSize       int            `gorm:"default:3" json:"ranked_ballot_size"`                                              // 排序投票模式下每张选票最多排序的作品数
+	RankedTallyMethod      string         `gorm:"type:enum('borda','irv');default:'borda'" json:"ranked_tally_method"`              // 排序投票计票方式：borda 波达计数，irv 即时决选
+	MaxVotesPerVoter       int            `gorm:"default:0" json:"max_votes_per_voter"`                                             // 每位投票人（或每支队伍）最多可投票数，0表示不限制
+	VoteScope              string         `gorm:"type:enum('participant','team');default:'participant'" json:"vote_scope"`          // 投票单位：participant 按参赛者，team 按队伍共享票数
+	AllowSelfVote          bool           `json:"allow_self_vote"`                                                                  // 是否允许为自己队伍的作品投票
+	AllowUncheckedVote     bool           `json:"allow_unchecked_vote"`                                                             // 是否允许已报名未签到的参赛者投票
+	BlindVoting            bool           `json:"blind_voting"`                                                                     // 是否在结果公布前对参赛者隐藏各作品得票数
+	PublicLeaderboard      bool           `json:"public_leaderboard"`                                                               // 是否向公众开放实时投票排行榜
+	JudgeWeight            int            `gorm:"default:0" json:"judge_weight"`                                                    // 最终得分中评委评分的权重（百分比，0-100），其余为投票权重
+	TieBreakRules          StringList     `gorm:"type:text" json:"tie_break_rules"`                                                 // 最终得分相同时依次使用的并列规则，为空使用默认顺序
+	ResultsPublishedAt     *time.Time     `json:"results_published_at"`                                                             // 比赛结果公布时间，为空表示尚未公布
+	ResultsDraftStale      bool           `json:"results_draft_stale"`                                                              // 结果草稿生成后投票被作废或恢复且未能更新草稿，需重新生成后才能公布
+	CreatedAt              time.Time      `json:"created_at"`
+	UpdatedAt              time.Time      `json:"updated_at"`
+	DeletedAt              gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// 关联关系
-	Organizer    User            `gorm:"foreignKey:OrganizerID" json:"organizer,omitempty"`
-	Stages       []HackathonStage `gorm:"foreignKey:HackathonID" json:"stages,omitempty"`
-	Awards       []HackathonAward `gorm:"foreignKey:HackathonID" json:"awards,omitempty"`
+	Organizer User             `gorm:"foreignKey:OrganizerID" json:"organizer,omitempty"`
+	Stages    []HackathonStage `gorm:"foreignKey:HackathonID" json:"stages,omitempty"`
+	Awards    []HackathonAward `gorm:"foreignKey:HackathonID" json:"awards,omitempty"`
+
+	SubmissionFields []HackathonSubmissionField `gorm:"foreignKey:HackathonID" json:"submission_fields,omitempty"`
 }
 
 // TableName 指定表名
@@ -89,3 +111,74 @@ func (HackathonPrize) TableName() string {
 	return "hackathon_prizes"
 }
 
+// HackathonWinner 活动获奖记录表（进入结果阶段时按名次和奖项数量生成）
+type HackathonWinner struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID  uint64    `gorm:"uniqueIndex:uk_hackathon_submission;not null" json:"hackathon_id"`
+	SubmissionID uint64    `gorm:"uniqueIndex:uk_hackathon_submission;not null" json:"submission_id"`
+	TeamID       uint64    `gorm:"index;not null" json:"team_id"`
+	AwardID      uint64    `gorm:"index;not null" json:"award_id"`
+	AwardName    string    `gorm:"type:varchar(100);not null" json:"award_name"` // 获奖时的奖项名称
+	Prize        string    `gorm:"type:varchar(255)" json:"prize"`               // 获奖时的奖金金额
+	Rank         int       `gorm:"not null" json:"rank"`                         // 最终名次
+	FinalScore   float64   `json:"final_score"`
+	VoteCount    int64     `json:"vote_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (HackathonWinner) TableName() string {
+	return "hackathon_winners"
+}
+
+// HackathonResultEntry 活动结果条目表（published 为0是主办方编辑中的草稿，为1是已公布的结果快照）
+type HackathonResultEntry struct {
+	ID              uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID     uint64    `gorm:"uniqueIndex:uk_hackathon_submission_published;not null" json:"hackathon_id"`
+	SubmissionID    uint64    `gorm:"uniqueIndex:uk_hackathon_submission_published;not null" json:"submission_id"`
+	Published       bool      `gorm:"uniqueIndex:uk_hackathon_submission_published;not null" json:"published"`
+	TeamID          uint64    `gorm:"not null" json:"team_id"`
+	SubmissionName  string    `gorm:"type:varchar(100)" json:"submission_name"`
+	TeamName        string    `gorm:"type:varchar(100)" json:"team_name"`
+	EngineRank      int       `gorm:"not null" json:"engine_rank"` // 结果引擎计算的名次
+	Rank            int       `gorm:"not null" json:"rank"`        // 名次（取消资格的作品不占名次，为0）
+	JudgeScore      float64   `json:"judge_score"`
+	JudgeCount      int       `json:"judge_count"`
+	VoteCount       int64     `json:"vote_count"`
+	NormalizedVotes float64   `json:"normalized_votes"`
+	JudgeWeight     int       `json:"judge_weight"`
+	FinalScore      float64   `json:"final_score"`
+	TieBreak        string    `gorm:"type:varchar(30)" json:"tie_break"`
+	AwardID         *uint64   `json:"award_id"`                                 // 所获奖项，为空表示未获奖
+	AwardOverridden bool      `json:"award_overridden"`                         // 奖项是否由主办方调整
+	SpecialMention  string    `gorm:"type:varchar(255)" json:"special_mention"` // 特别提名
+	Excluded        bool      `json:"excluded"`                                 // 是否被主办方取消资格（不计入公布的结果）
+	ExcludeReason   string    `gorm:"type:varchar(500)" json:"exclude_reason"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (HackathonResultEntry) TableName() string {
+	return "hackathon_result_entries"
+}
+
+// HackathonSubmissionField 活动自定义作品字段表（如路演PPT、合约地址、团队视频等）
+type HackathonSubmissionField struct {
+	ID          uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID uint64     `gorm:"uniqueIndex:uk_hackathon_field;not null" json:"hackathon_id"`
+	Key         string     `gorm:"column:field_key;uniqueIndex:uk_hackathon_field;type:varchar(50);not null" json:"key"` // 字段标识（作品 custom_fields 中的键）
+	Label       string     `gorm:"type:varchar(100);not null" json:"label"`
+	Type        string     `gorm:"type:enum('text','textarea','url','number','select','address');not null" json:"type"`
+	Required    bool       `json:"required"`                             // 是否必填（定稿时校验）
+	Options     StringList `gorm:"type:text" json:"options"`             // 可选项（仅 select 类型）
+	Placeholder string     `gorm:"type:varchar(255)" json:"placeholder"` // 填写提示
+	Order       int        `gorm:"column:sort_order;default:0" json:"order"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (HackathonSubmissionField) TableName() string {
+	return "hackathon_submission_fields"
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+)
+
+// HackathonJudge 活动评委表（主办方邀请 judge 角色的用户担任活动评委）
+type HackathonJudge struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID uint64    `gorm:"uniqueIndex:uk_hackathon_judge;not null" json:"hackathon_id"`
+	UserID      uint64    `gorm:"uniqueIndex:uk_hackathon_judge;not null" json:"user_id"`
+	Status      string    `gorm:"type:enum('active','inactive');default:'active'" json:"status"` // inactive-已退出评审，不再分配作品
+	CreatedAt   time.Time `json:"created_at"`
+
+	// 关联关系
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName 指定表名
+func (HackathonJudge) TableName() string {
+	return "hackathon_judges"
+}
+
+// JudgingCriterion 评分标准表（活动的评分维度、权重和分值范围）
+type JudgingCriterion struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID uint64    `gorm:"index;not null" json:"hackathon_id"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Description string    `gorm:"type:varchar(500)" json:"description"`
+	Weight      float64   `gorm:"not null" json:"weight"`     // 权重（按所有维度权重之和归一化）
+	MinScore    int       `gorm:"default:0" json:"min_score"` // 最低分
+	MaxScore    int       `gorm:"not null" json:"max_score"`  // 最高分
+	Order       int       `gorm:"column:sort_order;default:0" json:"order"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (JudgingCriterion) TableName() string {
+	return "judging_criteria"
+}
+
+// JudgeScore 评委评分表（每位评委对每个作品一条记录）
+type JudgeScore struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID  uint64    `gorm:"index;not null" json:"hackathon_id"`
+	SubmissionID uint64    `gorm:"uniqueIndex:uk_submission_judge;not null" json:"submission_id"`
+	JudgeID      uint64    `gorm:"uniqueIndex:uk_submission_judge;not null" json:"judge_id"`
+	Total        float64   `gorm:"not null" json:"total"` // 加权总分（0~100）
+	Comment      string    `gorm:"type:text" json:"comment"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// 关联关系
+	Judge User             `gorm:"foreignKey:JudgeID" json:"judge,omitempty"`
+	Items []JudgeScoreItem `gorm:"foreignKey:ScoreID" json:"items,omitempty"`
+}
+
+// TableName 指定表名
+func (JudgeScore) TableName() string {
+	return "judge_scores"
+}
+
+// JudgeScoreItem 评委评分明细表（每个评分维度的得分）
+type JudgeScoreItem struct {
+	ID          uint64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScoreID     uint64  `gorm:"uniqueIndex:uk_score_criterion;not null" json:"score_id"`
+	CriterionID uint64  `gorm:"uniqueIndex:uk_score_criterion;not null" json:"criterion_id"`
+	Score       float64 `gorm:"not null" json:"score"`
+}
+
+// TableName 指定表名
+func (JudgeScoreItem) TableName() string {
+	return "judge_score_items"
+}
+
+// JudgeConflict 评委利益冲突声明表
+// sponsor-与赞助商有关联（队伍成员钱包属于该赞助商账号时回避）
+// organization-所属组织（队伍成员所属组织相同时回避）
+// team-与队伍有关联（直接回避该队伍）
+type JudgeConflict struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID  uint64    `gorm:"index:idx_hackathon_judge;not null" json:"hackathon_id"`
+	JudgeID      uint64    `gorm:"index:idx_hackathon_judge;not null" json:"judge_id"`
+	Type         string    `gorm:"type:enum('sponsor','organization','team');not null" json:"type"`
+	SponsorID    *uint64   `json:"sponsor_id"`
+	Organization string    `gorm:"type:varchar(100)" json:"organization"`
+	TeamID       *uint64   `json:"team_id"`
+	Note         string    `gorm:"type:varchar(255)" json:"note"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (JudgeConflict) TableName() string {
+	return "judge_conflicts"
+}
+
+// JudgeAssignment 评委作品分配表（活动存在分配记录时，评委只能评审分配给自己的作品）
+type JudgeAssignment struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID  uint64    `gorm:"index;not null" json:"hackathon_id"`
+	SubmissionID uint64    `gorm:"uniqueIndex:uk_submission_judge;not null" json:"submission_id"`
+	JudgeID      uint64    `gorm:"uniqueIndex:uk_submission_judge;index;not null" json:"judge_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (JudgeAssignment) TableName() string {
+	return "judge_assignments"
+}
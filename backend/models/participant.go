@@ -11,6 +11,7 @@ type Participant struct {
 	ID            uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
 	WalletAddress string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"wallet_address"`
 	Nickname      string         `gorm:"type:varchar(50)" json:"nickname"` // 用户昵称
+	Organization  string         `gorm:"type:varchar(100)" json:"organization"` // 所属组织（公司、学校等），用于评委利益冲突检查
 	Nonce         string         `gorm:"type:varchar(255)" json:"-"`
 	LastLoginAt  *time.Time     `json:"last_login_at"`
 	CreatedAt    time.Time      `json:"created_at"`
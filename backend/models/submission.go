@@ -2,23 +2,66 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Submission 作品提交表
 type Submission struct {
-	ID          uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
-	HackathonID uint64    `gorm:"uniqueIndex:uk_hackathon_team;not null" json:"hackathon_id"`
-	TeamID      uint64    `gorm:"uniqueIndex:uk_hackathon_team;not null" json:"team_id"`
-	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
-	Description string    `gorm:"type:text;not null" json:"description"`
-	Link        string    `gorm:"type:varchar(500);not null" json:"link"`
-	Draft       int       `gorm:"type:tinyint(1);default:0" json:"draft"` // 1-草稿，0-已提交
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID  uint64     `gorm:"uniqueIndex:uk_hackathon_team;not null" json:"hackathon_id"`
+	TeamID       uint64     `gorm:"uniqueIndex:uk_hackathon_team;not null" json:"team_id"`
+	Name         string     `gorm:"type:varchar(100);not null" json:"name"`
+	Description  string     `gorm:"type:text;not null" json:"description"`
+	Link         string     `gorm:"type:varchar(500);not null" json:"link"`
+	RepoURL      string     `gorm:"type:varchar(500)" json:"repo_url"`    // 代码仓库地址
+	DemoURL      string     `gorm:"type:varchar(500)" json:"demo_url"`    // 在线演示地址
+	VideoURL     string     `gorm:"type:varchar(500)" json:"video_url"`   // 演示视频地址
+	TechStack    string     `gorm:"type:varchar(255)" json:"tech_stack"`  // 技术栈标签（逗号分隔）
+	CoverImage   string     `gorm:"type:varchar(500)" json:"cover_image"` // 封面图片地址
+	Screenshots  StringList `gorm:"type:text" json:"screenshots"`         // 截图地址列表
+	CustomFields StringMap  `gorm:"type:text" json:"custom_fields"`       // 活动自定义字段的值（键为字段标识）
+
+	// 代码仓库快照（提交截止时记录）
+	RepoHeadCommit         string     `gorm:"type:varchar(64)" json:"repo_head_commit"`
+	RepoCommitCount        int        `gorm:"default:0" json:"repo_commit_count"`
+	RepoCommitsBeforeStart int        `gorm:"default:0" json:"repo_commits_before_start"` // 早于活动开始的提交数
+	RepoCommitsAfterEnd    int        `gorm:"default:0" json:"repo_commits_after_end"`    // 晚于提交截止的提交数
+	RepoCheckStatus        string     `gorm:"type:varchar(20)" json:"repo_check_status"`  // verified/outside_window/error
+	RepoCheckMessage       string     `gorm:"type:varchar(500)" json:"repo_check_message"`
+	RepoSnapshotAt         *time.Time `json:"repo_snapshot_at"`
+
+	// 主办方审核（hidden-隐藏，disqualified-取消资格，二者均不公开展示、不参与投票和排名）
+	ModerationStatus string     `gorm:"type:enum('normal','flagged','hidden','disqualified');default:'normal'" json:"moderation_status"`
+	ModerationReason string     `gorm:"type:varchar(500)" json:"moderation_reason"`
+	ModeratedBy      *uint64    `json:"moderated_by"`
+	ModeratedAt      *time.Time `json:"moderated_at"`
+
+	// 主办方裁定的并列名次优先级（数值越小越靠前，0表示未裁定）
+	TieBreakPriority int `gorm:"default:0" json:"tie_break_priority"`
+
+	Draft              int        `gorm:"type:tinyint(1);default:0" json:"draft"` // 1-草稿，0-已定稿
+	FinalizedAt        *time.Time `json:"finalized_at"`                           // 定稿时间
+	DeadlineRemindedAt *time.Time `json:"deadline_reminded_at"`                   // 临近截止仍未定稿的提醒时间
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
 
 	// 关联关系
-	Hackathon Hackathon `gorm:"foreignKey:HackathonID" json:"hackathon,omitempty"`
-	Team      Team      `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+	Hackathon   Hackathon              `gorm:"foreignKey:HackathonID" json:"hackathon,omitempty"`
+	Team        Team                   `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+	Attachments []SubmissionAttachment `gorm:"foreignKey:SubmissionID" json:"attachments,omitempty"`
+	LinkChecks  []SubmissionLinkCheck  `gorm:"foreignKey:SubmissionID" json:"link_checks,omitempty"`
+
+	CustomFieldList []SubmissionCustomFieldValue `gorm:"-" json:"custom_field_list,omitempty"` // 按活动字段定义展示的自定义字段（不入库）
+	CommentCount    int64                        `gorm:"-" json:"comment_count"`               // 评论数（不入库）
+}
+
+// SubmissionCustomFieldValue 作品自定义字段的展示值
+type SubmissionCustomFieldValue struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
 }
 
 // TableName 指定表名
@@ -29,13 +72,17 @@ func (Submission) TableName() string {
 // Vote 投票记录表
 type Vote struct {
 	ID            uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
-	HackathonID  uint64    `gorm:"index;not null" json:"hackathon_id"`
+	HackathonID   uint64    `gorm:"index;not null" json:"hackathon_id"`
 	ParticipantID uint64    `gorm:"uniqueIndex:uk_participant_submission;not null" json:"participant_id"`
 	SubmissionID  uint64    `gorm:"uniqueIndex:uk_participant_submission;not null" json:"submission_id"`
+	Votes         int       `gorm:"default:1" json:"votes"`      // 票数（平方投票模式下为分配的票数，消耗票数平方的投票额度）
+	Voided        bool      `gorm:"default:false" json:"voided"` // 是否被主办方作废（作废的投票不计入票数）
+	ClientIP      string    `gorm:"type:varchar(45)" json:"-"`   // 投票时的IP地址
+	UserAgent     string    `gorm:"type:varchar(500)" json:"-"`  // 投票时的User-Agent
 	CreatedAt     time.Time `json:"created_at"`
 
 	// 关联关系
-	Hackathon  Hackathon  `gorm:"foreignKey:HackathonID" json:"hackathon,omitempty"`
+	Hackathon   Hackathon   `gorm:"foreignKey:HackathonID" json:"hackathon,omitempty"`
 	Participant Participant `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
 	Submission  Submission  `gorm:"foreignKey:SubmissionID" json:"submission,omitempty"`
 }
@@ -45,15 +92,85 @@ func (Vote) TableName() string {
 	return "votes"
 }
 
-// SubmissionHistory 作品修改记录表
-type SubmissionHistory struct {
+// RankedBallot 排序投票选票表（每位参赛者在一个活动中一张选票）
+type RankedBallot struct {
 	ID            uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
-	SubmissionID uint64    `gorm:"index;not null" json:"submission_id"`
-	ParticipantID uint64    `gorm:"index;not null" json:"participant_id"`
-	Name          string    `gorm:"type:varchar(100)" json:"name"`
-	Description   string    `gorm:"type:text" json:"description"`
-	Link          string    `gorm:"type:varchar(500)" json:"link"`
+	HackathonID   uint64    `gorm:"uniqueIndex:uk_hackathon_participant;not null" json:"hackathon_id"`
+	ParticipantID uint64    `gorm:"uniqueIndex:uk_hackathon_participant;not null" json:"participant_id"`
+	Voided        bool      `gorm:"default:false" json:"voided"` // 是否被主办方作废（作废的选票不参与计票）
+	ClientIP      string    `gorm:"type:varchar(45)" json:"-"`   // 提交选票时的IP地址
+	UserAgent     string    `gorm:"type:varchar(500)" json:"-"`  // 提交选票时的User-Agent
 	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// 关联关系
+	Items []RankedBallotItem `gorm:"foreignKey:BallotID" json:"items,omitempty"`
+}
+
+// TableName 指定表名
+func (RankedBallot) TableName() string {
+	return "ranked_ballots"
+}
+
+// RankedBallotItem 排序投票选票明细表
+type RankedBallotItem struct {
+	ID           uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	BallotID     uint64 `gorm:"uniqueIndex:uk_ballot_rank;uniqueIndex:uk_ballot_submission;not null" json:"ballot_id"`
+	SubmissionID uint64 `gorm:"uniqueIndex:uk_ballot_submission;not null" json:"submission_id"`
+	Rank         int    `gorm:"uniqueIndex:uk_ballot_rank;not null" json:"rank"` // 排名，从1开始
+
+	// 关联关系
+	Submission *Submission `gorm:"-" json:"submission,omitempty"`
+}
+
+// TableName 指定表名
+func (RankedBallotItem) TableName() string {
+	return "ranked_ballot_items"
+}
+
+// VoteFraudFlag 可疑投票标记表（每位投票人在一个活动中一条）
+// 不建立外键关联，参赛者被删除后标记仍保留
+type VoteFraudFlag struct {
+	ID              uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID     uint64     `gorm:"uniqueIndex:uk_hackathon_participant;not null" json:"hackathon_id"`
+	ParticipantID   uint64     `gorm:"uniqueIndex:uk_hackathon_participant;not null" json:"participant_id"`
+	WalletAddress   string     `gorm:"type:varchar(255)" json:"wallet_address"`
+	WalletCreatedAt time.Time  `json:"wallet_created_at"`                 // 参赛者钱包注册时间
+	Reasons         StringList `gorm:"type:text" json:"reasons"`          // 可疑原因：new_wallet/new_wallet_cluster/shared_fingerprint
+	Details         string     `gorm:"type:varchar(1000)" json:"details"` // 可疑原因说明
+	ClientIP        string     `gorm:"type:varchar(45)" json:"client_ip"`
+	UserAgent       string     `gorm:"type:varchar(500)" json:"user_agent"`
+	SubmissionIDs   StringList `gorm:"type:text" json:"submission_ids"` // 投票的作品ID
+	Status          string     `gorm:"type:enum('pending','dismissed','voided');default:'pending'" json:"status"`
+	ReviewedBy      *uint64    `json:"reviewed_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (VoteFraudFlag) TableName() string {
+	return "vote_fraud_flags"
+}
+
+// SubmissionHistory 作品修改记录表
+type SubmissionHistory struct {
+	ID            uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubmissionID  uint64     `gorm:"index;not null" json:"submission_id"`
+	ParticipantID uint64     `gorm:"index;not null" json:"participant_id"`
+	Name          string     `gorm:"type:varchar(100)" json:"name"`
+	Description   string     `gorm:"type:text" json:"description"`
+	Link          string     `gorm:"type:varchar(500)" json:"link"`
+	RepoURL       string     `gorm:"type:varchar(500)" json:"repo_url"`
+	DemoURL       string     `gorm:"type:varchar(500)" json:"demo_url"`
+	VideoURL      string     `gorm:"type:varchar(500)" json:"video_url"`
+	TechStack     string     `gorm:"type:varchar(255)" json:"tech_stack"`
+	CoverImage    string     `gorm:"type:varchar(500)" json:"cover_image"`
+	Screenshots   StringList `gorm:"type:text" json:"screenshots"`
+	CustomFields  StringMap  `gorm:"type:text" json:"custom_fields"`
+	Attachments   string     `gorm:"type:text" json:"-"` // 附件列表快照（JSON）
+	CreatedAt     time.Time  `json:"created_at"`
+
+	AttachmentList []SubmissionAttachment `gorm:"-" json:"attachments,omitempty"` // 解析后的附件快照（不入库）
 
 	// 关联关系
 	Submission  Submission  `gorm:"foreignKey:SubmissionID" json:"submission,omitempty"`
@@ -65,3 +182,95 @@ func (SubmissionHistory) TableName() string {
 	return "submission_histories"
 }
 
+// SubmissionAttachment 作品附件表（幻灯片、安装包、演示视频等）
+// 删除附件为软删除，文件保留以便从修改记录中追溯
+type SubmissionAttachment struct {
+	ID           uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubmissionID uint64         `gorm:"index;not null" json:"submission_id"`
+	UploaderID   uint64         `gorm:"index;not null" json:"uploader_id"`
+	FileName     string         `gorm:"type:varchar(255);not null" json:"file_name"` // 原始文件名
+	StorageKey   string         `gorm:"type:varchar(500);not null" json:"-"`         // 存储对象键
+	ContentType  string         `gorm:"type:varchar(100)" json:"content_type"`
+	Size         int64          `gorm:"not null" json:"size"`
+	CreatedAt    time.Time      `json:"created_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	DownloadURL string `gorm:"-" json:"download_url,omitempty"` // 签名下载链接（不入库）
+}
+
+// TableName 指定表名
+func (SubmissionAttachment) TableName() string {
+	return "submission_attachments"
+}
+
+// SubmissionLinkCheck 作品链接健康检查表（每个作品的每个链接字段一条记录）
+type SubmissionLinkCheck struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubmissionID  uint64    `gorm:"uniqueIndex:uk_submission_field;not null" json:"submission_id"`
+	Field         string    `gorm:"uniqueIndex:uk_submission_field;type:varchar(20);not null" json:"field"` // link/repo_url/demo_url/video_url/cover_image
+	URL           string    `gorm:"type:varchar(500);not null" json:"url"`
+	Healthy       bool      `json:"healthy"`
+	StatusCode    int       `gorm:"default:0" json:"status_code"` // 0 表示请求失败（超时、无法连接等）
+	LatencyMs     int64     `gorm:"default:0" json:"latency_ms"`
+	Error         string    `gorm:"type:varchar(255)" json:"error"`
+	FailCount     int       `gorm:"default:0" json:"fail_count"` // 连续失败次数
+	LastCheckedAt time.Time `json:"last_checked_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SubmissionLinkCheck) TableName() string {
+	return "submission_link_checks"
+}
+
+// SubmissionSimilarityReport 作品相似度报告表（疑似重复提交或抄袭）
+// 不建立外键关联，作品被删除后报告仍保留
+type SubmissionSimilarityReport struct {
+	ID                    uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID           uint64    `gorm:"index;not null" json:"hackathon_id"`
+	SubmissionID          uint64    `gorm:"uniqueIndex:uk_submission_matched;not null" json:"submission_id"`
+	SubmissionName        string    `gorm:"type:varchar(100)" json:"submission_name"`
+	MatchedSubmissionID   uint64    `gorm:"uniqueIndex:uk_submission_matched;not null" json:"matched_submission_id"`
+	MatchedSubmissionName string    `gorm:"type:varchar(100)" json:"matched_submission_name"`
+	MatchedHackathonID    uint64    `gorm:"not null" json:"matched_hackathon_id"`
+	MatchedHackathonName  string    `gorm:"type:varchar(100)" json:"matched_hackathon_name"`
+	Scope                 string    `gorm:"type:enum('hackathon','archive');not null" json:"scope"` // hackathon-同一活动，archive-往届活动
+	Score                 float64   `gorm:"not null" json:"score"`                                  // 综合相似度（0~1）
+	NameScore             float64   `json:"name_score"`
+	DescriptionScore      float64   `json:"description_score"`
+	MatchedLinks          string    `gorm:"type:varchar(1000)" json:"matched_links"` // 相同的链接（逗号分隔）
+	Status                string    `gorm:"type:enum('pending','confirmed','dismissed');default:'pending'" json:"status"`
+	ReviewedBy            *uint64   `json:"reviewed_by"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SubmissionSimilarityReport) TableName() string {
+	return "submission_similarity_reports"
+}
+
+// SubmissionComment 作品评论表（问答讨论，回复挂在顶层评论下）
+// 不建立与作品的外键关联，合并队伍时评论随作品转移
+type SubmissionComment struct {
+	ID            uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID   uint64         `gorm:"index;not null" json:"hackathon_id"`
+	SubmissionID  uint64         `gorm:"index:idx_submission_parent;not null" json:"submission_id"`
+	ParentID      *uint64        `gorm:"index:idx_submission_parent" json:"parent_id"` // 顶层评论为空
+	ParticipantID uint64         `gorm:"index;not null" json:"participant_id"`
+	Content       string         `gorm:"type:text;not null" json:"content"`
+	Official      bool           `json:"official"` // 作品所属队伍成员的官方回复
+	DeletedBy     *uint64        `json:"-"`        // 删除评论的主办方ID，参赛者删除自己的评论时为空
+	CreatedAt     time.Time      `json:"created_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// 关联关系
+	Participant Participant         `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
+	Replies     []SubmissionComment `gorm:"foreignKey:ParentID" json:"replies,omitempty"`
+}
+
+// TableName 指定表名
+func (SubmissionComment) TableName() string {
+	return "submission_comments"
+}
@@ -11,20 +11,23 @@ import (
 // - 一个队长在一个活动中只能创建一个队伍
 // - 同一个队长可以在不同活动中创建不同的队伍
 type Team struct {
-	ID          uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
-	HackathonID uint64         `gorm:"uniqueIndex:uk_hackathon_leader;not null" json:"hackathon_id"`
-	Name        string         `gorm:"type:varchar(50);not null" json:"name"`
-	LeaderID    uint64         `gorm:"uniqueIndex:uk_hackathon_leader;not null" json:"leader_id"`
-	MaxSize     int            `gorm:"default:3" json:"max_size"`
-	Status      string         `gorm:"type:enum('recruiting','locked');default:'recruiting'" json:"status"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID     uint64         `gorm:"uniqueIndex:uk_hackathon_leader;not null" json:"hackathon_id"`
+	Name            string         `gorm:"type:varchar(50);not null" json:"name"`
+	LeaderID        uint64         `gorm:"uniqueIndex:uk_hackathon_leader;not null" json:"leader_id"`
+	MaxSize         int            `gorm:"default:3" json:"max_size"`
+	Status          string         `gorm:"type:enum('recruiting','locked');default:'recruiting'" json:"status"`
+	LockedAt        *time.Time     `json:"locked_at"`         // 锁定时间
+	MinSizeOverride *int           `json:"min_size_override"` // 主办方特批的最小人数（为空则使用活动设置）
+	MaxSizeOverride *int           `json:"max_size_override"` // 主办方特批的最大人数（为空则使用活动设置）
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// 关联关系
-	Hackathon  Hackathon      `gorm:"foreignKey:HackathonID" json:"hackathon,omitempty"`
-	Leader     Participant    `gorm:"foreignKey:LeaderID" json:"leader,omitempty"`
-	Members    []TeamMember   `gorm:"foreignKey:TeamID" json:"members,omitempty"`
+	Hackathon Hackathon    `gorm:"foreignKey:HackathonID" json:"hackathon,omitempty"`
+	Leader    Participant  `gorm:"foreignKey:LeaderID" json:"leader,omitempty"`
+	Members   []TeamMember `gorm:"foreignKey:TeamID" json:"members,omitempty"`
 }
 
 // TableName 指定表名
@@ -41,7 +44,7 @@ type TeamMember struct {
 	JoinedAt      time.Time `json:"joined_at"`
 
 	// 关联关系
-	Team        Team       `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+	Team        Team        `gorm:"foreignKey:TeamID" json:"team,omitempty"`
 	Participant Participant `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
 }
 
@@ -50,3 +53,87 @@ func (TeamMember) TableName() string {
 	return "team_members"
 }
 
+// TeamMessage 队伍频道消息表
+type TeamMessage struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	TeamID        uint64    `gorm:"index:idx_team_created;not null" json:"team_id"`
+	ParticipantID *uint64   `gorm:"index" json:"participant_id"` // 系统消息为空
+	Type          string    `gorm:"type:enum('message','system');default:'message'" json:"type"`
+	Content       string    `gorm:"type:text;not null" json:"content"`
+	CreatedAt     time.Time `gorm:"index:idx_team_created" json:"created_at"`
+
+	// 关联关系
+	Participant *Participant `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
+}
+
+// TableName 指定表名
+func (TeamMessage) TableName() string {
+	return "team_messages"
+}
+
+// TeamSeekingPost 求组队帖子表（未组队的参赛者自我介绍）
+type TeamSeekingPost struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID   uint64    `gorm:"uniqueIndex:uk_hackathon_participant;not null" json:"hackathon_id"`
+	ParticipantID uint64    `gorm:"uniqueIndex:uk_hackathon_participant;not null" json:"participant_id"`
+	Pitch         string    `gorm:"type:varchar(500);not null" json:"pitch"` // 自我介绍
+	Skills        string    `gorm:"type:varchar(255)" json:"skills"`         // 技能标签，逗号分隔
+	Status        string    `gorm:"type:enum('open','closed');default:'open'" json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// 关联关系
+	Participant Participant `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
+}
+
+// TableName 指定表名
+func (TeamSeekingPost) TableName() string {
+	return "team_seeking_posts"
+}
+
+// TeamInvitation 队伍邀请表（队长邀请求组队的参赛者）
+type TeamInvitation struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID   uint64    `gorm:"index;not null" json:"hackathon_id"`
+	TeamID        uint64    `gorm:"index;not null" json:"team_id"`
+	ParticipantID uint64    `gorm:"index;not null" json:"participant_id"` // 被邀请人
+	InviterID     uint64    `gorm:"not null" json:"inviter_id"`           // 邀请人（队长）
+	Message       string    `gorm:"type:varchar(500)" json:"message"`
+	Status        string    `gorm:"type:enum('pending','accepted','declined','expired');default:'pending'" json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// 关联关系
+	Team        Team        `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+	Participant Participant `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
+}
+
+// TableName 指定表名
+func (TeamInvitation) TableName() string {
+	return "team_invitations"
+}
+
+// TeamMergeRequest 队伍合并申请表
+// 两支队伍的队长都同意后，被合并队伍的成员转入保留队伍，被合并队伍解散
+// 被合并队伍解散后记录仍保留，因此不建立队伍外键关联
+type TeamMergeRequest struct {
+	ID             uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	HackathonID    uint64     `gorm:"index;not null" json:"hackathon_id"`
+	SourceTeamID   uint64     `gorm:"index;not null" json:"source_team_id"` // 被合并（解散）的队伍
+	TargetTeamID   uint64     `gorm:"index;not null" json:"target_team_id"` // 保留的队伍
+	SourceTeamName string     `gorm:"type:varchar(50)" json:"source_team_name"`
+	TargetTeamName string     `gorm:"type:varchar(50)" json:"target_team_name"`
+	RequesterID    uint64     `gorm:"not null" json:"requester_id"` // 发起申请的队长
+	Message        string     `gorm:"type:varchar(500)" json:"message"`
+	SourceApproved bool       `gorm:"default:false" json:"source_approved"`
+	TargetApproved bool       `gorm:"default:false" json:"target_approved"`
+	Status         string     `gorm:"type:enum('pending','completed','rejected','cancelled');default:'pending'" json:"status"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (TeamMergeRequest) TableName() string {
+	return "team_merge_requests"
+}
@@ -6,13 +6,13 @@ import (
 	"gorm.io/gorm"
 )
 
-// User 用户表（管理员、主办方、赞助商）
+// User 用户表（管理员、主办方、赞助商、评委）
 type User struct {
 	ID        uint64         `gorm:"primaryKey;autoIncrement" json:"id"`
 	Name      string         `gorm:"type:varchar(100);not null" json:"name"`
 	Phone     string         `gorm:"type:varchar(20);uniqueIndex" json:"phone"` // 手机号，唯一但不强制（web3登录可能没有）
 	Password  string         `gorm:"type:varchar(255)" json:"-"` // 不返回密码，可为空（web3登录不需要密码）
-	Role      string         `gorm:"type:enum('admin','organizer','sponsor');not null" json:"role"`
+	Role      string         `gorm:"type:enum('admin','organizer','sponsor','judge');not null" json:"role"`
 	SponsorID *uint64        `gorm:"index" json:"sponsor_id"`
 	Status    int            `gorm:"type:tinyint(1);default:1" json:"status"` // 1-启用，0-禁用
 	CreatedAt time.Time      `json:"created_at"`
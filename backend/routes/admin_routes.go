@@ -12,6 +12,11 @@ func SetupAdminRoutes(router *gin.Engine) {
 	adminUserController := controllers.NewAdminUserController()
 	adminHackathonController := controllers.NewAdminHackathonController()
 	adminDashboardController := controllers.NewAdminDashboardController()
+	adminTeamController := controllers.NewAdminTeamController()
+	adminSubmissionController := controllers.NewAdminSubmissionController()
+	adminJudgingController := controllers.NewAdminJudgingController()
+	adminResultController := controllers.NewAdminResultController()
+	judgeController := controllers.NewJudgeController()
 	sponsorController := controllers.NewSponsorController()
 
 	api := router.Group("/api/v1/admin")
@@ -84,6 +89,62 @@ func SetupAdminRoutes(router *gin.Engine) {
 				hackathons.POST("/:id/stages/:stage/switch", middleware.RoleMiddleware("organizer"), adminHackathonController.SwitchStage)
 				hackathons.GET("/:id/stages", middleware.RoleMiddleware("organizer", "admin"), adminHackathonController.GetStageTimes)
 				hackathons.PUT("/:id/stages", middleware.RoleMiddleware("organizer"), adminHackathonController.UpdateStageTimes)
+				hackathons.GET("/:id/submission-fields", middleware.RoleMiddleware("organizer", "admin"), adminHackathonController.GetSubmissionFields)
+				hackathons.PUT("/:id/submission-fields", middleware.RoleMiddleware("organizer"), adminHackathonController.UpdateSubmissionFields)
+				hackathons.GET("/:id/voting-rules", middleware.RoleMiddleware("organizer", "admin"), adminHackathonController.GetVotingRules)
+				hackathons.PUT("/:id/voting-rules", middleware.RoleMiddleware("organizer"), adminHackathonController.UpdateVotingRules)
+
+				// 队伍管理（仅Organizer，且仅活动创建者）
+				hackathons.POST("/:id/teams/:team_id/lock", middleware.RoleMiddleware("organizer"), adminTeamController.LockTeam)
+				hackathons.POST("/:id/teams/:team_id/unlock", middleware.RoleMiddleware("organizer"), adminTeamController.UnlockTeam)
+				hackathons.PUT("/:id/teams/:team_id/size-override", middleware.RoleMiddleware("organizer"), adminTeamController.SetTeamSizeOverride)
+
+				// 作品管理（Organizer和Admin可查看，操作仅活动创建者）
+				hackathons.GET("/:id/submissions", middleware.RoleMiddleware("organizer", "admin"), adminSubmissionController.GetSubmissionList)
+				hackathons.PUT("/:id/submissions/:submission_id/moderation", middleware.RoleMiddleware("organizer"), adminSubmissionController.ModerateSubmission)
+				hackathons.GET("/:id/submission-comments", middleware.RoleMiddleware("organizer", "admin"), adminSubmissionController.GetComments)
+				hackathons.DELETE("/:id/submission-comments/:comment_id", middleware.RoleMiddleware("organizer"), adminSubmissionController.DeleteComment)
+				hackathons.GET("/:id/repo-snapshots", middleware.RoleMiddleware("organizer", "admin"), adminSubmissionController.GetRepoSnapshots)
+				hackathons.POST("/:id/repo-snapshots", middleware.RoleMiddleware("organizer"), adminSubmissionController.RunRepoSnapshots)
+				hackathons.GET("/:id/draft-submissions", middleware.RoleMiddleware("organizer", "admin"), adminSubmissionController.GetDraftSubmissions)
+				hackathons.GET("/:id/broken-links", middleware.RoleMiddleware("organizer", "admin"), adminSubmissionController.GetBrokenLinks)
+				hackathons.POST("/:id/link-checks", middleware.RoleMiddleware("organizer"), adminSubmissionController.RunLinkChecks)
+				hackathons.GET("/:id/similarity-reports", middleware.RoleMiddleware("organizer", "admin"), adminSubmissionController.GetSimilarityReports)
+				hackathons.POST("/:id/similarity-reports", middleware.RoleMiddleware("organizer"), adminSubmissionController.AnalyzeSimilarity)
+				hackathons.PUT("/:id/similarity-reports/:report_id", middleware.RoleMiddleware("organizer"), adminSubmissionController.ReviewSimilarityReport)
+
+				// 评审管理（Organizer和Admin可查看，设置仅活动创建者）
+				hackathons.GET("/:id/judging-criteria", middleware.RoleMiddleware("organizer", "admin"), adminJudgingController.GetCriteria)
+				hackathons.PUT("/:id/judging-criteria", middleware.RoleMiddleware("organizer"), adminJudgingController.UpdateCriteria)
+				hackathons.GET("/:id/judges", middleware.RoleMiddleware("organizer", "admin"), adminJudgingController.GetJudges)
+				hackathons.POST("/:id/judges", middleware.RoleMiddleware("organizer"), adminJudgingController.AddJudge)
+				hackathons.DELETE("/:id/judges/:user_id", middleware.RoleMiddleware("organizer"), adminJudgingController.RemoveJudge)
+				hackathons.GET("/:id/judge-scores", middleware.RoleMiddleware("organizer", "admin"), adminJudgingController.GetScoreSummary)
+				hackathons.POST("/:id/judges/:user_id/reassign", middleware.RoleMiddleware("organizer"), adminJudgingController.ReassignJudge)
+				hackathons.GET("/:id/judge-conflicts", middleware.RoleMiddleware("organizer", "admin"), adminJudgingController.GetConflicts)
+				hackathons.POST("/:id/judge-conflicts", middleware.RoleMiddleware("organizer"), adminJudgingController.DeclareConflict)
+				hackathons.DELETE("/:id/judge-conflicts/:conflict_id", middleware.RoleMiddleware("organizer"), adminJudgingController.DeleteConflict)
+				hackathons.GET("/:id/judge-assignments", middleware.RoleMiddleware("organizer", "admin"), adminJudgingController.GetAssignments)
+				hackathons.POST("/:id/judge-assignments", middleware.RoleMiddleware("organizer"), adminJudgingController.AssignJudges)
+
+				// 结果排名（Organizer和Admin可查看，设置仅活动创建者）
+				hackathons.GET("/:id/scoring-rules", middleware.RoleMiddleware("organizer", "admin"), adminResultController.GetScoringRules)
+				hackathons.PUT("/:id/scoring-rules", middleware.RoleMiddleware("organizer"), adminResultController.UpdateScoringRules)
+				hackathons.GET("/:id/rankings", middleware.RoleMiddleware("organizer", "admin"), adminResultController.GetRankings)
+				hackathons.PUT("/:id/submissions/:submission_id/tie-break", middleware.RoleMiddleware("organizer"), adminResultController.SetTieBreakPriority)
+				hackathons.GET("/:id/results/draft", middleware.RoleMiddleware("organizer", "admin"), adminResultController.GetResultDraft)
+				hackathons.POST("/:id/results/draft", middleware.RoleMiddleware("organizer"), adminResultController.GenerateResultDraft)
+				hackathons.PUT("/:id/results/draft/:submission_id", middleware.RoleMiddleware("organizer"), adminResultController.UpdateResultDraftEntry)
+				hackathons.POST("/:id/results/publish", middleware.RoleMiddleware("organizer"), adminResultController.PublishResults)
+
+				// 可疑投票检测（Organizer和Admin可查看，分析和处理仅活动创建者）
+				hackathons.GET("/:id/vote-fraud-flags", middleware.RoleMiddleware("organizer", "admin"), adminResultController.GetVoteFraudFlags)
+				hackathons.POST("/:id/vote-fraud-flags", middleware.RoleMiddleware("organizer"), adminResultController.AnalyzeVoteFraud)
+				hackathons.PUT("/:id/vote-fraud-flags/:flag_id", middleware.RoleMiddleware("organizer"), adminResultController.ReviewVoteFraudFlag)
+
+				// 实时投票排行榜（Organizer和Admin）
+				hackathons.GET("/:id/leaderboard", middleware.RoleMiddleware("organizer", "admin"), adminResultController.GetVoteLeaderboard)
+				hackathons.GET("/:id/leaderboard/stream", middleware.RoleMiddleware("organizer", "admin"), adminResultController.StreamVoteLeaderboard)
 
 				// 归档活动（Organizer和Admin都可以，但需检查权限）
 				hackathons.POST("/:id/archive", middleware.RoleMiddleware("organizer", "admin"), adminHackathonController.ArchiveHackathon)
@@ -91,6 +152,20 @@ func SetupAdminRoutes(router *gin.Engine) {
 				hackathons.POST("/batch-archive", middleware.RoleMiddleware("organizer", "admin"), adminHackathonController.BatchArchiveHackathons)
 			}
 
+			// 评委评审（Judge权限）
+			judging := api.Group("/judging")
+			judging.Use(middleware.RoleMiddleware("judge"))
+			{
+				judging.GET("/hackathons", judgeController.GetMyHackathons)
+				judging.GET("/hackathons/:id/criteria", judgeController.GetCriteria)
+				judging.GET("/hackathons/:id/submissions", judgeController.GetSubmissions)
+				judging.GET("/hackathons/:id/conflicts", judgeController.GetMyConflicts)
+				judging.POST("/hackathons/:id/conflicts", judgeController.DeclareConflict)
+				judging.DELETE("/hackathons/:id/conflicts/:conflict_id", judgeController.DeleteConflict)
+				judging.GET("/submissions/:id", judgeController.GetSubmission)
+				judging.PUT("/submissions/:id/score", judgeController.SubmitScore)
+			}
+
 			// 赞助商审核（Admin权限）
 			sponsorAdmin := api.Group("/sponsor")
 			sponsorAdmin.Use(middleware.RoleMiddleware("admin"))
@@ -12,7 +12,12 @@ func SetupArenaRoutes(router *gin.Engine) {
 	arenaHackathonController := controllers.NewArenaHackathonController()
 	arenaRegistrationController := controllers.NewArenaRegistrationController()
 	arenaTeamController := controllers.NewArenaTeamController()
+	arenaTeamChannelController := controllers.NewArenaTeamChannelController()
+	arenaTeamBoardController := controllers.NewArenaTeamBoardController()
+	arenaTeamMergeController := controllers.NewArenaTeamMergeController()
 	arenaSubmissionController := controllers.NewArenaSubmissionController()
+	arenaSubmissionAttachmentController := controllers.NewArenaSubmissionAttachmentController()
+	arenaSubmissionCommentController := controllers.NewArenaSubmissionCommentController()
 	arenaVoteController := controllers.NewArenaVoteController()
 
 	api := router.Group("/api/v1/arena")
@@ -31,6 +36,8 @@ func SetupArenaRoutes(router *gin.Engine) {
 			hackathons.GET("/:id", arenaHackathonController.GetHackathonByID)
 			hackathons.GET("/archive", arenaHackathonController.GetArchiveList)
 			hackathons.GET("/archive/:id", arenaHackathonController.GetArchiveDetail)
+			hackathons.GET("/:id/leaderboard", arenaVoteController.GetLeaderboard)
+			hackathons.GET("/:id/leaderboard/stream", arenaVoteController.StreamLeaderboard)
 		}
 
 		// 赞助商相关（无需认证）
@@ -41,6 +48,9 @@ func SetupArenaRoutes(router *gin.Engine) {
 			sponsors.GET("/events/:id", sponsorController.GetEventSponsors)
 		}
 
+		// 附件下载（签名链接，无需认证）
+		api.GET("/attachments/:id/download", arenaSubmissionAttachmentController.DownloadAttachment)
+
 		// 需要认证的路由
 		api.Use(middleware.ParticipantAuthMiddleware())
 		{
@@ -78,22 +88,69 @@ func SetupArenaRoutes(router *gin.Engine) {
 			api.DELETE("/teams/:id", arenaTeamController.DissolveTeam)
 			api.DELETE("/teams/:id/members/:member_id", arenaTeamController.RemoveMember)
 			api.PATCH("/teams/:id", arenaTeamController.UpdateTeam)
+			api.POST("/teams/:id/lock", arenaTeamController.LockTeam)
+			api.POST("/teams/:id/unlock", arenaTeamController.UnlockTeam)
+
+			// 队伍频道（仅队伍成员）
+			api.GET("/teams/:id/ws", arenaTeamChannelController.Connect)
+			api.GET("/teams/:id/messages", arenaTeamChannelController.GetMessages)
+			api.GET("/teams/:id/online", arenaTeamChannelController.GetOnlineMembers)
+
+			// 求组队广场
+			board := api.Group("/hackathons/:id/team-board")
+			{
+				board.GET("", arenaTeamBoardController.GetPostList)
+				board.GET("/my-post", arenaTeamBoardController.GetMyPost)
+				board.PUT("/my-post", arenaTeamBoardController.SavePost)
+				board.DELETE("/my-post", arenaTeamBoardController.ClosePost)
+				board.GET("/my-invitations", arenaTeamBoardController.GetMyInvitations)
+			}
+
+			api.POST("/team-board/posts/:id/invite", arenaTeamBoardController.SendInvitation)
+			api.GET("/teams/:id/invitations", arenaTeamBoardController.GetTeamInvitations)
+			api.POST("/team-invitations/:id/accept", arenaTeamBoardController.AcceptInvitation)
+			api.POST("/team-invitations/:id/decline", arenaTeamBoardController.DeclineInvitation)
+
+			// 队伍合并（仅队长）
+			api.POST("/teams/:id/merge-requests", arenaTeamMergeController.RequestMerge)
+			api.GET("/teams/:id/merge-requests", arenaTeamMergeController.GetMergeRequests)
+			api.POST("/team-merge-requests/:id/approve", arenaTeamMergeController.ApproveMerge)
+			api.POST("/team-merge-requests/:id/reject", arenaTeamMergeController.RejectMerge)
 
 			// 作品提交相关
 			submissions := api.Group("/hackathons/:id/submissions")
 			{
 				submissions.POST("", arenaSubmissionController.CreateSubmission)
 				submissions.GET("", arenaSubmissionController.GetSubmissionList)
+				submissions.GET("/my", arenaSubmissionController.GetMySubmission)
 			}
 
 			api.GET("/submissions/:id", arenaSubmissionController.GetSubmissionByID)
 			api.PUT("/submissions/:id", arenaSubmissionController.UpdateSubmission)
 			api.GET("/submissions/:id/history", arenaSubmissionController.GetSubmissionHistory)
+			api.GET("/submissions/:id/diff", arenaSubmissionController.GetSubmissionDiff)
+			api.POST("/submissions/:id/restore", arenaSubmissionController.RestoreSubmission)
+			api.POST("/submissions/:id/finalize", arenaSubmissionController.FinalizeSubmission)
+			api.POST("/submissions/:id/unfinalize", arenaSubmissionController.UnfinalizeSubmission)
+
+			// 作品附件
+			api.POST("/submissions/:id/attachments", arenaSubmissionAttachmentController.UploadAttachment)
+			api.GET("/submissions/:id/attachments", arenaSubmissionAttachmentController.GetAttachments)
+			api.DELETE("/submissions/:id/attachments/:attachment_id", arenaSubmissionAttachmentController.DeleteAttachment)
+
+			// 作品评论
+			api.POST("/submissions/:id/comments", arenaSubmissionCommentController.CreateComment)
+			api.GET("/submissions/:id/comments", arenaSubmissionCommentController.GetComments)
+			api.DELETE("/submission-comments/:id", arenaSubmissionCommentController.DeleteComment)
 
 			// 投票相关
 			api.POST("/submissions/:id/vote", arenaVoteController.Vote)
 			api.DELETE("/submissions/:id/vote", arenaVoteController.CancelVote)
 			api.GET("/hackathons/:id/votes", arenaVoteController.GetMyVotes)
+			api.PUT("/hackathons/:id/vote-allocations", arenaVoteController.AllocateVotes)
+			api.PUT("/hackathons/:id/ballot", arenaVoteController.SubmitBallot)
+			api.GET("/hackathons/:id/ballot", arenaVoteController.GetMyBallot)
+			api.DELETE("/hackathons/:id/ballot", arenaVoteController.CancelBallot)
 
 			// 结果查看
 			api.GET("/hackathons/:id/results", arenaVoteController.GetResults)
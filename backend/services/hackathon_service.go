@@ -16,9 +16,23 @@ type HackathonService struct{}
 
 // CreateHackathon 创建活动
 func (s *HackathonService) CreateHackathon(hackathon *models.Hackathon, stages []models.HackathonStage, awards []models.HackathonAward, autoAssignStages bool) error {
+	// 校验队伍人数设置
+	if err := s.validateTeamSize(hackathon.MinTeamSize, hackathon.MaxTeamSize); err != nil {
+		return err
+	}
+
+	// 校验投票规则和评分规则
+	if err := validateVotingRules(votingRulesOf(hackathon)); err != nil {
+		return err
+	}
+	if err := validateScoringRules(hackathon.JudgeWeight, hackathon.TieBreakRules); err != nil {
+		return err
+	}
+
 	return database.DB.Transaction(func(tx *gorm.DB) error {
 		// 创建活动
-		if err := tx.Create(hackathon).Error; err != nil {
+		// 自定义作品字段通过单独的接口设置，结果公布时间由公布结果时记录
+		if err := tx.Omit("SubmissionFields", "ResultsPublishedAt", "ResultsDraftStale").Create(hackathon).Error; err != nil {
 			return fmt.Errorf("创建活动失败: %w", err)
 		}
 
@@ -162,7 +176,11 @@ func (s *HackathonService) GetHackathonList(page, pageSize int, status, keyword,
 // GetHackathonByID 根据ID获取活动详情
 func (s *HackathonService) GetHackathonByID(id uint64) (*models.Hackathon, error) {
 	var hackathon models.Hackathon
-	if err := database.DB.Preload("Stages").Preload("Awards").Where("id = ? AND deleted_at IS NULL", id).First(&hackathon).Error; err != nil {
+	if err := database.DB.Preload("Stages").Preload("Awards").
+		Preload("SubmissionFields", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC, id ASC")
+		}).
+		Where("id = ? AND deleted_at IS NULL", id).First(&hackathon).Error; err != nil {
 		return nil, err
 	}
 
@@ -177,8 +195,8 @@ func (s *HackathonService) GetHackathonStats(id uint64) (map[string]interface{},
 	database.DB.Model(&models.Registration{}).Where("hackathon_id = ?", id).Count(&registrationCount)
 	database.DB.Model(&models.Checkin{}).Where("hackathon_id = ?", id).Count(&checkinCount)
 	database.DB.Model(&models.Team{}).Where("hackathon_id = ? AND deleted_at IS NULL", id).Count(&teamCount)
-	database.DB.Model(&models.Submission{}).Where("hackathon_id = ? AND draft = 0", id).Count(&submissionCount)
-	database.DB.Model(&models.Vote{}).Where("hackathon_id = ?", id).Count(&voteCount)
+	database.DB.Model(&models.Submission{}).Where("hackathon_id = ? AND draft = 0 AND moderation_status IN ?", id, PublicModerationStatuses).Count(&submissionCount)
+	voteCount = (&VoteService{}).GetHackathonVoteCount(id)
 
 	stats["registration_count"] = registrationCount
 	stats["checkin_count"] = checkinCount
@@ -304,7 +322,7 @@ func (s *HackathonService) GetHackathonStatsDetail(hackathonID uint64, statsType
 		// 作品数量详情
 		query := database.DB.Model(&models.Submission{}).
 			Joins("INNER JOIN teams ON teams.id = submissions.team_id").
-			Where("submissions.hackathon_id = ? AND submissions.draft = 0", hackathonID)
+			Where("submissions.hackathon_id = ? AND submissions.draft = 0 AND submissions.moderation_status IN ?", hackathonID, PublicModerationStatuses)
 
 		if keyword != "" {
 			query = query.Where("submissions.name LIKE ? OR teams.name LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
@@ -395,9 +413,22 @@ func (s *HackathonService) UpdateHackathon(id uint64, hackathon *models.Hackatho
 		})
 	}
 
+	// 校验队伍人数设置（未传入的字段沿用原值）
+	minTeamSize, maxTeamSize := existing.MinTeamSize, existing.MaxTeamSize
+	if hackathon.MinTeamSize != 0 {
+		minTeamSize = hackathon.MinTeamSize
+	}
+	if hackathon.MaxTeamSize != 0 {
+		maxTeamSize = hackathon.MaxTeamSize
+	}
+	if err := s.validateTeamSize(minTeamSize, maxTeamSize); err != nil {
+		return err
+	}
+
 	return database.DB.Transaction(func(tx *gorm.DB) error {
-		// 更新活动
-		if err := tx.Model(&models.Hackathon{}).Where("id = ?", id).Updates(hackathon).Error; err != nil {
+		// 更新活动（投票规则、评分规则和作品必填设置通过单独的接口设置）
+		omitted := append([]string{"SubmissionFields", "ResultsPublishedAt", "ResultsDraftStale", "RequireRepoURL"}, votingRuleColumns...)
+		if err := tx.Model(&models.Hackathon{}).Where("id = ?", id).Omit(append(omitted, scoringRuleColumns...)...).Updates(hackathon).Error; err != nil {
 			return err
 		}
 
@@ -549,7 +580,8 @@ func (s *HackathonService) GetPosterQRCode(hackathonID uint64) (string, error) {
 }
 
 // SwitchStage 切换活动阶段（仅活动创建者可切换）
-func (s *HackathonService) SwitchStage(id uint64, stage string, userID uint64, userRole string) error {
+// 离开组队阶段时返回因人数不足未被锁定的队伍
+func (s *HackathonService) SwitchStage(id uint64, stage string, userID uint64, userRole string) ([]models.Team, error) {
 	validStages := map[string]bool{
 		"published":      true,
 		"registration":   true,
@@ -561,25 +593,65 @@ func (s *HackathonService) SwitchStage(id uint64, stage string, userID uint64, u
 	}
 
 	if !validStages[stage] {
-		return errors.New("无效的阶段")
+		return nil, errors.New("无效的阶段")
 	}
 
 	var hackathon models.Hackathon
 	if err := database.DB.Where("id = ? AND deleted_at IS NULL", id).First(&hackathon).Error; err != nil {
-		return err
+		return nil, err
 	}
 
 	// Admin不能切换阶段
 	if userRole == "admin" {
-		return errors.New("Admin不能切换活动阶段")
+		return nil, errors.New("Admin不能切换活动阶段")
 	}
 
 	// 检查是否是活动创建者
 	if hackathon.OrganizerID != userID {
-		return errors.New("只能切换自己创建的活动阶段")
+		return nil, errors.New("只能切换自己创建的活动阶段")
 	}
 
-	return database.DB.Model(&hackathon).Update("status", stage).Error
+	previousStatus := hackathon.Status
+	var underSizeTeams []models.Team
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&hackathon).Update("status", stage).Error; err != nil {
+			return err
+		}
+
+		// 离开组队阶段时自动锁定达到最小人数的队伍
+		if previousStatus == "team_formation" && stage != "team_formation" {
+			teamService := &TeamService{}
+			teams, err := teamService.LockHackathonTeams(tx, &hackathon)
+			if err != nil {
+				return fmt.Errorf("锁定队伍失败: %w", err)
+			}
+			underSizeTeams = teams
+		}
+
+		// 进入结果阶段时由结果引擎按最终的投票和评分重新生成草稿供主办方审核后公布
+		// 投票阶段已生成的草稿中主办方的调整（奖项、特别提名和取消资格）保留
+		if stage == "results" && previousStatus != "results" {
+			resultService := &ResultService{}
+			if err := resultService.generateDraft(tx, &hackathon, true); err != nil {
+				return fmt.Errorf("生成结果草稿失败: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 通知人数不足的队伍继续补充成员
+	teamService := &TeamService{}
+	channelService := &TeamChannelService{}
+	for _, team := range underSizeTeams {
+		minSize, _ := teamService.TeamSizeBounds(&team, &hackathon)
+		channelService.PublishSystemEvent(team.ID, fmt.Sprintf("组队阶段已结束，队伍人数不足%d人暂未锁定，请尽快补充成员，人数达标后将自动锁定", minSize))
+	}
+
+	return underSizeTeams, nil
 }
 
 // GetPublishedHackathons 获取已发布的活动列表（Arena平台）
@@ -772,6 +844,17 @@ func (s *HackathonService) GetStageTimes(hackathonID uint64) ([]models.Hackathon
 	return stages, nil
 }
 
+// validateTeamSize 验证队伍人数设置（0表示使用默认值）
+func (s *HackathonService) validateTeamSize(minTeamSize, maxTeamSize int) error {
+	if minTeamSize < 0 || maxTeamSize < 0 {
+		return errors.New("队伍人数不能为负数")
+	}
+	if minTeamSize > 0 && maxTeamSize > 0 && minTeamSize > maxTeamSize {
+		return errors.New("最小队伍人数不能大于最大队伍人数")
+	}
+	return nil
+}
+
 // validateStageTimes 验证阶段时间
 func (s *HackathonService) validateStageTimes(hackathonID uint64, stages []models.HackathonStage, hackathon *models.Hackathon) error {
 	// 阶段顺序
@@ -832,7 +915,7 @@ func (s *HackathonService) GetArchiveHackathons(page, pageSize int, keyword, tim
 	var total int64
 
 	query := database.DB.Model(&models.Hackathon{}).
-		Where("deleted_at IS NULL AND status = 'results'")
+		Where("deleted_at IS NULL AND status = 'results' AND results_published_at IS NOT NULL")
 
 	// 时间范围筛选
 	if timeRange != "" && timeRange != "all" {
@@ -881,6 +964,9 @@ func (s *HackathonService) GetArchiveDetail(hackathonID uint64) (map[string]inte
 	if hackathon.Status != "results" {
 		return nil, errors.New("活动尚未结束")
 	}
+	if hackathon.ResultsPublishedAt == nil {
+		return nil, errors.New("活动结果尚未公布")
+	}
 
 	// 获取统计信息
 	stats, err := s.GetHackathonStats(hackathonID)
@@ -888,87 +974,82 @@ func (s *HackathonService) GetArchiveDetail(hackathonID uint64) (map[string]inte
 		return nil, err
 	}
 
-	// 获取所有作品
-	var submissions []models.Submission
-	if err := database.DB.
-		Preload("Team").
-		Where("hackathon_id = ? AND draft = 0", hackathonID).
-		Find(&submissions).Error; err != nil {
+	// 已公布的比赛结果（与比赛结果页一致）
+	resultService := &ResultService{}
+	entries, err := resultService.GetPublishedEntries(hackathon)
+	if err != nil {
 		return nil, err
 	}
 
-	// 计算每个作品的得票数
-	submissionVoteCounts := make(map[uint64]int64)
-	voteService := &VoteService{}
-	for _, submission := range submissions {
-		count, _ := voteService.GetVoteCount(submission.ID)
-		submissionVoteCounts[submission.ID] = count
+	// 按名次排列的作品
+	submissions := make([]models.Submission, 0, len(entries))
+	for _, entry := range entries {
+		submissions = append(submissions, entry.Submission)
 	}
 
-	// 获取投票结果
-	var votes []models.Vote
-	if err := database.DB.
-		Where("hackathon_id = ?", hackathonID).
-		Find(&votes).Error; err != nil {
-		return nil, err
-	}
+	// 按活动字段定义展示自定义字段
+	fieldService := &SubmissionFieldService{}
+	fieldService.FillCustomFieldList(hackathonID, submissions)
 
-	// 计算每个作品的得票数和得票率
+	// 计算每个作品的得票数和得票率（仅统计参与排名作品的投票）
 	voteResults := make([]map[string]interface{}, 0)
-	totalVotes := int64(len(votes))
-	for _, submission := range submissions {
-		voteCount := submissionVoteCounts[submission.ID]
+	var totalVotes int64
+	for _, entry := range entries {
+		totalVotes += entry.Breakdown.VoteCount
+	}
+	for _, entry := range entries {
 		var voteRate float64
 		if totalVotes > 0 {
-			voteRate = float64(voteCount) / float64(totalVotes) * 100
+			voteRate = float64(entry.Breakdown.VoteCount) / float64(totalVotes) * 100
 		}
 		voteResults = append(voteResults, map[string]interface{}{
-			"submission_id":   submission.ID,
-			"submission_name": submission.Name,
-			"team_name":       submission.Team.Name,
-			"vote_count":      voteCount,
+			"submission_id":   entry.Submission.ID,
+			"submission_name": entry.Submission.Name,
+			"team_name":       entry.Submission.Team.Name,
+			"vote_count":      entry.Breakdown.VoteCount,
 			"vote_rate":       voteRate,
+			"final_score":     entry.Breakdown.FinalScore,
+			"special_mention": entry.SpecialMention,
 		})
 	}
 
-	// 按得票数排序作品
-	for i := 0; i < len(submissions)-1; i++ {
-		for j := i + 1; j < len(submissions); j++ {
-			if submissionVoteCounts[submissions[i].ID] < submissionVoteCounts[submissions[j].ID] {
-				submissions[i], submissions[j] = submissions[j], submissions[i]
-			}
+	// 比赛结果（获奖队伍）按已公布结果中的奖项分组（奖项在公布后被修改或删除时使用获奖记录中的快照）
+	// 奖项按其最高名次获奖作品的先后排列
+	finalResults := make([]map[string]interface{}, 0)
+	awardIndex := make(map[uint64]int)
+	for _, entry := range entries {
+		if entry.Award == nil {
+			continue
 		}
-	}
-
-	// 获取比赛结果（获奖队伍）
-	var awards []models.HackathonAward
-	if err := database.DB.Where("hackathon_id = ?", hackathonID).Order("`rank` ASC").Find(&awards).Error; err != nil {
-		return nil, err
-	}
-
-		finalResults := make([]map[string]interface{}, 0)
-		submissionIndex := 0
-		for _, award := range awards {
-			// 根据奖项排名获取对应的作品（按得票数排序后的前N个）
-			awardResults := make([]map[string]interface{}, 0)
-			for i := 0; i < award.Quantity && submissionIndex < len(submissions); i++ {
-				submission := submissions[submissionIndex]
-				voteCount := submissionVoteCounts[submission.ID]
-				awardResults = append(awardResults, map[string]interface{}{
-					"team_name":      submission.Team.Name,
-					"submission_name": submission.Name,
-					"vote_count":      voteCount,
-					"prize_money":     award.Prize,
-				})
-				submissionIndex++
-			}
+		award := entry.Award
+		index, ok := awardIndex[award.ID]
+		if !ok {
+			index = len(finalResults)
+			awardIndex[award.ID] = index
 			finalResults = append(finalResults, map[string]interface{}{
 				"award_name": award.Name,
 				"prize":      award.Prize,
 				"quantity":   award.Quantity,
-				"winners":    awardResults,
+				"winners":    make([]map[string]interface{}, 0),
 			})
 		}
+		group := finalResults[index]
+		group["winners"] = append(group["winners"].([]map[string]interface{}), map[string]interface{}{
+			"rank":            entry.Rank,
+			"team_name":       entry.Submission.Team.Name,
+			"submission_name": entry.Submission.Name,
+			"vote_count":      entry.Breakdown.VoteCount,
+			"final_score":     entry.Breakdown.FinalScore,
+			"prize_money":     award.Prize,
+			"special_mention": entry.SpecialMention,
+		})
+	}
+	// 已删除奖项的快照没有名额信息，名额至少为获奖作品数
+	for _, group := range finalResults {
+		if winners := len(group["winners"].([]map[string]interface{})); group["quantity"].(int) < winners {
+			group["quantity"] = winners
+		}
+	}
 
 	return map[string]interface{}{
 		"hackathon":    hackathon,
@@ -0,0 +1,511 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type JudgeAssignmentService struct{}
+
+// JudgeConflictInput 声明利益冲突的参数
+type JudgeConflictInput struct {
+	Type         string  `json:"type" binding:"required"`
+	SponsorID    *uint64 `json:"sponsor_id"`
+	Organization string  `json:"organization"`
+	TeamID       *uint64 `json:"team_id"`
+	Note         string  `json:"note"`
+}
+
+// AssignmentResult 分配结果
+type AssignmentResult struct {
+	ReviewsPerSubmission int                   `json:"reviews_per_submission"`
+	Created              int                   `json:"created"`     // 新增的分配数
+	Shortfalls           []AssignmentShortfall `json:"shortfalls"`  // 可用评委不足、未达到评审人数的作品
+	JudgeLoads           map[uint64]int        `json:"judge_loads"` // 每位评委分配的作品数
+}
+
+// AssignmentShortfall 未达到评审人数的作品
+type AssignmentShortfall struct {
+	SubmissionID   uint64 `json:"submission_id"`
+	SubmissionName string `json:"submission_name"`
+	Assigned       int    `json:"assigned"`
+}
+
+// GetConflicts 获取活动的利益冲突声明（judgeID 为0时返回全部评委的声明）
+func (s *JudgeAssignmentService) GetConflicts(hackathonID, judgeID uint64) ([]models.JudgeConflict, error) {
+	query := database.DB.Where("hackathon_id = ?", hackathonID)
+	if judgeID != 0 {
+		query = query.Where("judge_id = ?", judgeID)
+	}
+
+	var conflicts []models.JudgeConflict
+	if err := query.Order("judge_id ASC, id ASC").Find(&conflicts).Error; err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// DeclareConflict 声明利益冲突（评委本人或活动创建者代为声明），已分配的冲突作品会被取消分配
+func (s *JudgeAssignmentService) DeclareConflict(hackathonID, judgeID uint64, input JudgeConflictInput) (*models.JudgeConflict, error) {
+	judgingService := &JudgingService{}
+	if !judgingService.IsJudge(hackathonID, judgeID) {
+		return nil, errors.New("该用户不是活动评委")
+	}
+
+	conflict := models.JudgeConflict{
+		HackathonID: hackathonID,
+		JudgeID:     judgeID,
+		Type:        input.Type,
+		Note:        strings.TrimSpace(input.Note),
+	}
+
+	switch input.Type {
+	case "sponsor":
+		if input.SponsorID == nil {
+			return nil, errors.New("请选择关联的赞助商")
+		}
+		var sponsor models.Sponsor
+		if err := database.DB.Where("id = ?", *input.SponsorID).First(&sponsor).Error; err != nil {
+			return nil, errors.New("赞助商不存在")
+		}
+		conflict.SponsorID = input.SponsorID
+	case "organization":
+		conflict.Organization = strings.TrimSpace(input.Organization)
+		if conflict.Organization == "" {
+			return nil, errors.New("请填写所属组织")
+		}
+	case "team":
+		if input.TeamID == nil {
+			return nil, errors.New("请选择关联的队伍")
+		}
+		var team models.Team
+		if err := database.DB.Where("id = ? AND hackathon_id = ?", *input.TeamID, hackathonID).First(&team).Error; err != nil {
+			return nil, errors.New("队伍不存在")
+		}
+		conflict.TeamID = input.TeamID
+	default:
+		return nil, errors.New("无效的冲突类型")
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&conflict).Error; err != nil {
+			return err
+		}
+		return s.releaseConflictedAssignments(tx, hackathonID, judgeID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &conflict, nil
+}
+
+// DeleteConflict 删除利益冲突声明（judgeID 为0时表示活动创建者操作，可删除任意评委的声明）
+func (s *JudgeAssignmentService) DeleteConflict(hackathonID, conflictID, judgeID uint64) error {
+	query := database.DB.Where("id = ? AND hackathon_id = ?", conflictID, hackathonID)
+	if judgeID != 0 {
+		query = query.Where("judge_id = ?", judgeID)
+	}
+
+	result := query.Delete(&models.JudgeConflict{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("冲突声明不存在")
+	}
+	return nil
+}
+
+// GetAssignments 获取活动的评委分配情况（每位评委的作品和每个作品的评委）
+func (s *JudgeAssignmentService) GetAssignments(hackathonID uint64) (map[string]interface{}, error) {
+	var assignments []models.JudgeAssignment
+	if err := database.DB.Where("hackathon_id = ?", hackathonID).Order("submission_id ASC, judge_id ASC").Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+
+	scored := s.scoredPairs(hackathonID)
+	byJudge := make(map[uint64][]map[string]interface{})
+	bySubmission := make(map[uint64][]uint64)
+	for _, assignment := range assignments {
+		byJudge[assignment.JudgeID] = append(byJudge[assignment.JudgeID], map[string]interface{}{
+			"submission_id": assignment.SubmissionID,
+			"scored":        scored[[2]uint64{assignment.SubmissionID, assignment.JudgeID}],
+		})
+		bySubmission[assignment.SubmissionID] = append(bySubmission[assignment.SubmissionID], assignment.JudgeID)
+	}
+
+	return map[string]interface{}{
+		"assignments":   assignments,
+		"by_judge":      byJudge,
+		"by_submission": bySubmission,
+	}, nil
+}
+
+// AssignJudges 为活动作品分配评委，使每个作品获得 N 位评委评审
+// 已有分配保留，只补足缺少的评审；优先处理可用评委最少的作品，每次选择当前负载最小的评委，并回避利益冲突
+func (s *JudgeAssignmentService) AssignJudges(hackathonID uint64, reviewsPerSubmission int) (*AssignmentResult, error) {
+	hackathon, err := s.loadAssignableHackathon(hackathonID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *AssignmentResult
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		result, err = s.assignJudges(tx, hackathon, reviewsPerSubmission)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReassignJudge 评委退出评审：标记为已退出，将其尚未评分的作品重新分配给其他评委
+// 退出和重新分配在同一事务中完成，重新分配失败时评委保持原状态
+func (s *JudgeAssignmentService) ReassignJudge(hackathonID, judgeID uint64) (*AssignmentResult, error) {
+	hackathon, err := s.loadAssignableHackathon(hackathonID)
+	if err != nil {
+		return nil, err
+	}
+
+	var judge models.HackathonJudge
+	if err := database.DB.Where("hackathon_id = ? AND user_id = ?", hackathonID, judgeID).First(&judge).Error; err != nil {
+		return nil, errors.New("该用户不是活动评委")
+	}
+
+	var result *AssignmentResult
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&judge).Update("status", "inactive").Error; err != nil {
+			return err
+		}
+		if err := s.releaseUnscoredAssignments(tx, hackathonID, judgeID, nil); err != nil {
+			return err
+		}
+		var err error
+		result, err = s.assignJudges(tx, hackathon, 0)
+		if err != nil {
+			return fmt.Errorf("重新分配作品失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// loadAssignableHackathon 加载可以分配评委的活动（结果公布后不能再分配）
+func (s *JudgeAssignmentService) loadAssignableHackathon(hackathonID uint64) (*models.Hackathon, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+	if hackathon.Status == "results" {
+		return nil, errors.New("结果已公布，无法分配评委")
+	}
+	return &hackathon, nil
+}
+
+// assignJudges 在事务中为活动作品补足评委分配
+func (s *JudgeAssignmentService) assignJudges(tx *gorm.DB, hackathon *models.Hackathon, reviewsPerSubmission int) (*AssignmentResult, error) {
+	hackathonID := hackathon.ID
+	if reviewsPerSubmission <= 0 {
+		reviewsPerSubmission = hackathon.ReviewsPerSubmission
+	} else if reviewsPerSubmission != hackathon.ReviewsPerSubmission {
+		if err := tx.Model(hackathon).UpdateColumn("reviews_per_submission", reviewsPerSubmission).Error; err != nil {
+			return nil, err
+		}
+	}
+	if reviewsPerSubmission <= 0 {
+		return nil, errors.New("每个作品的评审人数必须大于0")
+	}
+
+	var judges []models.HackathonJudge
+	if err := tx.Where("hackathon_id = ? AND status = ?", hackathonID, "active").Order("user_id ASC").Find(&judges).Error; err != nil {
+		return nil, err
+	}
+	if len(judges) == 0 {
+		return nil, errors.New("活动还没有评委")
+	}
+
+	var submissions []models.Submission
+	if err := tx.Where("hackathon_id = ? AND draft = 0 AND moderation_status IN ?", hackathonID, PublicModerationStatuses).
+		Order("id ASC").Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+
+	var existing []models.JudgeAssignment
+	if err := tx.Where("hackathon_id = ?", hackathonID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	conflicts, err := s.conflictedTeams(hackathonID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 当前负载和已分配关系（包括已退出评委的分配，它们仍计入作品的评审人数）
+	loads := make(map[uint64]int)
+	for _, judge := range judges {
+		loads[judge.UserID] = 0
+	}
+	assigned := make(map[uint64]map[uint64]bool)
+	for _, assignment := range existing {
+		if _, ok := loads[assignment.JudgeID]; ok {
+			loads[assignment.JudgeID]++
+		}
+		if assigned[assignment.SubmissionID] == nil {
+			assigned[assignment.SubmissionID] = make(map[uint64]bool)
+		}
+		assigned[assignment.SubmissionID][assignment.JudgeID] = true
+	}
+
+	eligible := func(submission *models.Submission, judgeID uint64) bool {
+		return !assigned[submission.ID][judgeID] && conflicts[judgeID][submission.TeamID] == ""
+	}
+
+	// 可用评委少的作品优先分配，避免被负载均衡挤占
+	sort.SliceStable(submissions, func(i, j int) bool {
+		ci, cj := 0, 0
+		for _, judge := range judges {
+			if eligible(&submissions[i], judge.UserID) {
+				ci++
+			}
+			if eligible(&submissions[j], judge.UserID) {
+				cj++
+			}
+		}
+		return ci < cj
+	})
+
+	result := &AssignmentResult{ReviewsPerSubmission: reviewsPerSubmission, Shortfalls: make([]AssignmentShortfall, 0)}
+	newAssignments := make([]models.JudgeAssignment, 0)
+	for i := range submissions {
+		submission := &submissions[i]
+		for len(assigned[submission.ID]) < reviewsPerSubmission {
+			var best uint64
+			bestLoad := -1
+			for _, judge := range judges {
+				if !eligible(submission, judge.UserID) {
+					continue
+				}
+				if bestLoad == -1 || loads[judge.UserID] < bestLoad {
+					best, bestLoad = judge.UserID, loads[judge.UserID]
+				}
+			}
+			if bestLoad == -1 {
+				break
+			}
+
+			if assigned[submission.ID] == nil {
+				assigned[submission.ID] = make(map[uint64]bool)
+			}
+			assigned[submission.ID][best] = true
+			loads[best]++
+			newAssignments = append(newAssignments, models.JudgeAssignment{
+				HackathonID:  hackathonID,
+				SubmissionID: submission.ID,
+				JudgeID:      best,
+			})
+		}
+		if count := len(assigned[submission.ID]); count < reviewsPerSubmission {
+			result.Shortfalls = append(result.Shortfalls, AssignmentShortfall{
+				SubmissionID:   submission.ID,
+				SubmissionName: submission.Name,
+				Assigned:       count,
+			})
+		}
+	}
+
+	if len(newAssignments) > 0 {
+		if err := tx.Create(&newAssignments).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	result.Created = len(newAssignments)
+	result.JudgeLoads = loads
+	return result, nil
+}
+
+// IsAssigned 检查评委能否评审作品：活动没有分配记录时所有评委都可评审，否则只能评审分配给自己的作品
+func (s *JudgeAssignmentService) IsAssigned(hackathonID, submissionID, judgeID uint64) bool {
+	var total int64
+	database.DB.Model(&models.JudgeAssignment{}).Where("hackathon_id = ?", hackathonID).Count(&total)
+	if total == 0 {
+		return true
+	}
+
+	var count int64
+	database.DB.Model(&models.JudgeAssignment{}).Where("submission_id = ? AND judge_id = ?", submissionID, judgeID).Count(&count)
+	return count > 0
+}
+
+// AssignedSubmissionIDs 获取分配给评委的作品ID（活动没有分配记录时返回 nil，表示不限制）
+func (s *JudgeAssignmentService) AssignedSubmissionIDs(hackathonID, judgeID uint64) ([]uint64, error) {
+	var total int64
+	database.DB.Model(&models.JudgeAssignment{}).Where("hackathon_id = ?", hackathonID).Count(&total)
+	if total == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint64, 0)
+	if err := database.DB.Model(&models.JudgeAssignment{}).
+		Where("hackathon_id = ? AND judge_id = ?", hackathonID, judgeID).
+		Pluck("submission_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ConflictReason 返回评委与作品所属队伍的利益冲突原因（无冲突时为空）
+func (s *JudgeAssignmentService) ConflictReason(hackathonID, teamID, judgeID uint64) string {
+	conflicts, err := s.conflictedTeams(hackathonID)
+	if err != nil {
+		return ""
+	}
+	return conflicts[judgeID][teamID]
+}
+
+// releaseConflictedAssignments 取消评委与冲突队伍作品的未评分分配
+func (s *JudgeAssignmentService) releaseConflictedAssignments(tx *gorm.DB, hackathonID, judgeID uint64) error {
+	conflicts, err := s.conflictedTeams(hackathonID)
+	if err != nil {
+		return err
+	}
+	if len(conflicts[judgeID]) == 0 {
+		return nil
+	}
+
+	teamIDs := make([]uint64, 0, len(conflicts[judgeID]))
+	for teamID := range conflicts[judgeID] {
+		teamIDs = append(teamIDs, teamID)
+	}
+	return s.releaseUnscoredAssignments(tx, hackathonID, judgeID, teamIDs)
+}
+
+// releaseUnscoredAssignments 删除评委尚未评分的分配（teamIDs 不为空时只删除这些队伍的作品）
+func (s *JudgeAssignmentService) releaseUnscoredAssignments(tx *gorm.DB, hackathonID, judgeID uint64, teamIDs []uint64) error {
+	query := tx.Where("hackathon_id = ? AND judge_id = ?", hackathonID, judgeID).
+		Where("submission_id NOT IN (?)", tx.Model(&models.JudgeScore{}).Select("submission_id").Where("judge_id = ?", judgeID))
+	if teamIDs != nil {
+		query = query.Where("submission_id IN (?)", tx.Model(&models.Submission{}).Select("id").Where("team_id IN ?", teamIDs))
+	}
+	return query.Delete(&models.JudgeAssignment{}).Error
+}
+
+// scoredPairs 获取已评分的（作品，评委）组合
+func (s *JudgeAssignmentService) scoredPairs(hackathonID uint64) map[[2]uint64]bool {
+	var scores []models.JudgeScore
+	database.DB.Select("submission_id, judge_id").Where("hackathon_id = ?", hackathonID).Find(&scores)
+
+	result := make(map[[2]uint64]bool)
+	for _, score := range scores {
+		result[[2]uint64{score.SubmissionID, score.JudgeID}] = true
+	}
+	return result
+}
+
+// conflictedTeams 计算每位评委需要回避的队伍及原因
+// 包括声明的队伍、所属组织相同的队伍、成员钱包属于声明的赞助商（或评委本人所属赞助商）账号的队伍，以及成员钱包属于评委本人的队伍
+func (s *JudgeAssignmentService) conflictedTeams(hackathonID uint64) (map[uint64]map[uint64]string, error) {
+	var judges []models.HackathonJudge
+	if err := database.DB.Preload("User").Where("hackathon_id = ?", hackathonID).Find(&judges).Error; err != nil {
+		return nil, err
+	}
+
+	var teams []models.Team
+	if err := database.DB.Preload("Members").Preload("Members.Participant").
+		Where("hackathon_id = ? AND deleted_at IS NULL", hackathonID).Find(&teams).Error; err != nil {
+		return nil, err
+	}
+
+	conflicts, err := s.GetConflicts(hackathonID, 0)
+	if err != nil {
+		return nil, err
+	}
+	declared := make(map[uint64][]models.JudgeConflict)
+	for _, conflict := range conflicts {
+		declared[conflict.JudgeID] = append(declared[conflict.JudgeID], conflict)
+	}
+
+	result := make(map[uint64]map[uint64]string)
+	for _, judge := range judges {
+		reasons := make(map[uint64]string)
+		result[judge.UserID] = reasons
+
+		judgeWallets := userWalletSet([]uint64{judge.UserID})
+		sponsorIDs := make([]uint64, 0)
+		if judge.User.SponsorID != nil {
+			sponsorIDs = append(sponsorIDs, *judge.User.SponsorID)
+		}
+		organizations := make(map[string]bool)
+		for _, conflict := range declared[judge.UserID] {
+			switch conflict.Type {
+			case "team":
+				reasons[*conflict.TeamID] = "声明与该队伍有关联"
+			case "organization":
+				organizations[strings.ToLower(conflict.Organization)] = true
+			case "sponsor":
+				sponsorIDs = append(sponsorIDs, *conflict.SponsorID)
+			}
+		}
+		sponsorWallets := sponsorWalletSet(sponsorIDs)
+
+		for _, team := range teams {
+			if reasons[team.ID] != "" {
+				continue
+			}
+			for _, member := range team.Members {
+				wallet := strings.ToLower(member.Participant.WalletAddress)
+				organization := strings.ToLower(strings.TrimSpace(member.Participant.Organization))
+				if judgeWallets[wallet] {
+					reasons[team.ID] = "评委本人是队伍成员"
+				} else if sponsorWallets[wallet] {
+					reasons[team.ID] = "队伍成员属于关联的赞助商"
+				} else if organization != "" && organizations[organization] {
+					reasons[team.ID] = fmt.Sprintf("队伍成员与评委同属%s", member.Participant.Organization)
+				}
+				if reasons[team.ID] != "" {
+					break
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// userWalletSet 获取用户绑定的钱包地址（小写）
+func userWalletSet(userIDs []uint64) map[string]bool {
+	result := make(map[string]bool)
+	if len(userIDs) == 0 {
+		return result
+	}
+	var wallets []models.UserWallet
+	database.DB.Where("user_id IN ?", userIDs).Find(&wallets)
+	for _, wallet := range wallets {
+		result[strings.ToLower(wallet.Address)] = true
+	}
+	return result
+}
+
+// sponsorWalletSet 获取赞助商账号绑定的钱包地址（小写）
+func sponsorWalletSet(sponsorIDs []uint64) map[string]bool {
+	if len(sponsorIDs) == 0 {
+		return make(map[string]bool)
+	}
+	userIDs := make([]uint64, 0)
+	database.DB.Model(&models.User{}).Where("sponsor_id IN ?", sponsorIDs).Pluck("id", &userIDs)
+
+	var sponsorUserIDs []uint64
+	database.DB.Model(&models.Sponsor{}).Where("id IN ?", sponsorIDs).Pluck("user_id", &sponsorUserIDs)
+	return userWalletSet(append(userIDs, sponsorUserIDs...))
+}
@@ -0,0 +1,482 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type JudgingService struct{}
+
+// JudgeScoreInput 评委提交的单个维度得分
+type JudgeScoreInput struct {
+	CriterionID uint64  `json:"criterion_id" binding:"required"`
+	Score       float64 `json:"score"`
+}
+
+// SubmissionJudgeSummary 作品的评委评分汇总
+type SubmissionJudgeSummary struct {
+	SubmissionID    uint64              `json:"submission_id"`
+	SubmissionName  string              `json:"submission_name"`
+	TeamName        string              `json:"team_name"`
+	JudgeCount      int                 `json:"judge_count"`
+	MeanScore       float64             `json:"mean_score"`       // 加权总分的平均值（0~100）
+	NormalizedScore float64             `json:"normalized_score"` // 按评委标准化（z-score）后的平均值，消除评委打分松紧的差异
+	CriterionMeans  map[uint64]float64  `json:"criterion_means"`  // 各维度得分的平均值
+	Scores          []models.JudgeScore `json:"scores"`
+}
+
+// GetCriteria 获取活动的评分标准
+func (s *JudgingService) GetCriteria(hackathonID uint64) ([]models.JudgingCriterion, error) {
+	var criteria []models.JudgingCriterion
+	if err := database.DB.Where("hackathon_id = ?", hackathonID).Order("sort_order ASC, id ASC").Find(&criteria).Error; err != nil {
+		return nil, err
+	}
+	return criteria, nil
+}
+
+// UpdateCriteria 整体替换活动的评分标准（仅活动创建者，评委开始评分后不能修改）
+func (s *JudgingService) UpdateCriteria(hackathonID uint64, criteria []models.JudgingCriterion, userID uint64, userRole string) error {
+	if _, err := s.checkOrganizer(hackathonID, userID, userRole); err != nil {
+		return err
+	}
+
+	var scoreCount int64
+	database.DB.Model(&models.JudgeScore{}).Where("hackathon_id = ?", hackathonID).Count(&scoreCount)
+	if scoreCount > 0 {
+		return errors.New("评委已开始评分，不能修改评分标准")
+	}
+
+	for i := range criteria {
+		criterion := &criteria[i]
+		criterion.Name = strings.TrimSpace(criterion.Name)
+		if criterion.Name == "" {
+			return errors.New("评分维度名称不能为空")
+		}
+		if criterion.Weight <= 0 {
+			return fmt.Errorf("评分维度 %s 的权重必须大于0", criterion.Name)
+		}
+		if criterion.MaxScore <= criterion.MinScore {
+			return fmt.Errorf("评分维度 %s 的最高分必须大于最低分", criterion.Name)
+		}
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("hackathon_id = ?", hackathonID).Delete(&models.JudgingCriterion{}).Error; err != nil {
+			return err
+		}
+		for i := range criteria {
+			criteria[i].ID = 0
+			criteria[i].HackathonID = hackathonID
+			if err := tx.Create(&criteria[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetJudges 获取活动评委列表
+func (s *JudgingService) GetJudges(hackathonID uint64) ([]models.HackathonJudge, error) {
+	var judges []models.HackathonJudge
+	if err := database.DB.Preload("User").Where("hackathon_id = ?", hackathonID).Order("id ASC").Find(&judges).Error; err != nil {
+		return nil, err
+	}
+	return judges, nil
+}
+
+// AddJudge 添加活动评委（仅活动创建者，用户必须是已启用的 judge 角色）
+func (s *JudgingService) AddJudge(hackathonID, judgeUserID, userID uint64, userRole string) (*models.HackathonJudge, error) {
+	if _, err := s.checkOrganizer(hackathonID, userID, userRole); err != nil {
+		return nil, err
+	}
+
+	var user models.User
+	if err := database.DB.Where("id = ? AND role = ? AND status = 1 AND deleted_at IS NULL", judgeUserID, "judge").First(&user).Error; err != nil {
+		return nil, errors.New("评委用户不存在或已禁用")
+	}
+
+	var existing models.HackathonJudge
+	if err := database.DB.Where("hackathon_id = ? AND user_id = ?", hackathonID, judgeUserID).First(&existing).Error; err == nil {
+		return nil, errors.New("该用户已是活动评委")
+	}
+
+	judge := models.HackathonJudge{HackathonID: hackathonID, UserID: judgeUserID}
+	if err := database.DB.Create(&judge).Error; err != nil {
+		return nil, err
+	}
+	judge.User = user
+	return &judge, nil
+}
+
+// RemoveJudge 移除活动评委（仅活动创建者，已提交的评分保留，未评分的作品需重新分配）
+func (s *JudgingService) RemoveJudge(hackathonID, judgeUserID, userID uint64, userRole string) error {
+	if _, err := s.checkOrganizer(hackathonID, userID, userRole); err != nil {
+		return err
+	}
+
+	// 已退出评审的评委也可以移除
+	var judge models.HackathonJudge
+	if err := database.DB.Where("hackathon_id = ? AND user_id = ?", hackathonID, judgeUserID).First(&judge).Error; err != nil {
+		return errors.New("该用户不是活动评委")
+	}
+
+	// 同时取消其尚未评分的作品分配
+	assignmentService := &JudgeAssignmentService{}
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("hackathon_id = ? AND user_id = ?", hackathonID, judgeUserID).Delete(&models.HackathonJudge{}).Error; err != nil {
+			return err
+		}
+		return assignmentService.releaseUnscoredAssignments(tx, hackathonID, judgeUserID, nil)
+	})
+}
+
+// IsJudge 检查用户是否为活动评委（已退出评审的评委不能再评分）
+func (s *JudgingService) IsJudge(hackathonID, judgeID uint64) bool {
+	var count int64
+	database.DB.Model(&models.HackathonJudge{}).Where("hackathon_id = ? AND user_id = ? AND status = ?", hackathonID, judgeID, "active").Count(&count)
+	return count > 0
+}
+
+// GetJudgeHackathons 获取评委参与评审的活动
+func (s *JudgingService) GetJudgeHackathons(judgeID uint64) ([]models.Hackathon, error) {
+	var hackathons []models.Hackathon
+	if err := database.DB.Joins("JOIN hackathon_judges ON hackathon_judges.hackathon_id = hackathons.id").
+		Where("hackathon_judges.user_id = ? AND hackathons.deleted_at IS NULL", judgeID).
+		Order("hackathons.start_time DESC").
+		Find(&hackathons).Error; err != nil {
+		return nil, err
+	}
+	return hackathons, nil
+}
+
+// GetJudgeSubmissions 获取评委待评审的作品及自己的评分
+func (s *JudgingService) GetJudgeSubmissions(hackathonID, judgeID uint64) ([]map[string]interface{}, error) {
+	if !s.IsJudge(hackathonID, judgeID) {
+		return nil, errors.New("您不是该活动的评委")
+	}
+
+	// 活动已分配评委时只返回分配给自己的作品，否则返回没有利益冲突的全部作品
+	assignmentService := &JudgeAssignmentService{}
+	assignedIDs, err := assignmentService.AssignedSubmissionIDs(hackathonID, judgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := database.DB.Preload("Team").
+		Where("hackathon_id = ? AND draft = 0 AND moderation_status IN ?", hackathonID, PublicModerationStatuses)
+	if assignedIDs != nil {
+		query = query.Where("id IN ?", append(assignedIDs, 0))
+	} else {
+		conflicts, err := assignmentService.conflictedTeams(hackathonID)
+		if err != nil {
+			return nil, err
+		}
+		teamIDs := []uint64{0}
+		for teamID := range conflicts[judgeID] {
+			teamIDs = append(teamIDs, teamID)
+		}
+		query = query.Where("team_id NOT IN ?", teamIDs)
+	}
+
+	var submissions []models.Submission
+	if err := query.Order("id ASC").Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+
+	var scores []models.JudgeScore
+	if err := database.DB.Preload("Items").Where("hackathon_id = ? AND judge_id = ?", hackathonID, judgeID).Find(&scores).Error; err != nil {
+		return nil, err
+	}
+	scoreMap := make(map[uint64]models.JudgeScore)
+	for _, score := range scores {
+		scoreMap[score.SubmissionID] = score
+	}
+
+	result := make([]map[string]interface{}, 0, len(submissions))
+	for _, submission := range submissions {
+		item := map[string]interface{}{
+			"submission": submission,
+			"scored":     false,
+			"score":      nil,
+		}
+		if score, ok := scoreMap[submission.ID]; ok {
+			item["scored"] = true
+			item["score"] = score
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// GetJudgeSubmission 获取评委评审的作品详情及自己的评分
+func (s *JudgingService) GetJudgeSubmission(submissionID, judgeID uint64) (map[string]interface{}, error) {
+	submission, err := s.loadJudgeSubmission(submissionID, judgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	submissionService := &SubmissionService{}
+	detail, err := submissionService.GetSubmissionByID(submission.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	criteria, err := s.GetCriteria(submission.HackathonID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"submission": detail,
+		"criteria":   criteria,
+		"score":      nil,
+	}
+	var score models.JudgeScore
+	if err := database.DB.Preload("Items").Where("submission_id = ? AND judge_id = ?", submissionID, judgeID).First(&score).Error; err == nil {
+		result["score"] = score
+	}
+	return result, nil
+}
+
+// SubmitScore 评委提交或修改作品评分（投票阶段内，每个评分维度都必须打分）
+func (s *JudgingService) SubmitScore(submissionID, judgeID uint64, inputs []JudgeScoreInput, comment string) (*models.JudgeScore, error) {
+	submission, err := s.loadJudgeSubmission(submissionID, judgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", submission.HackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+	if hackathon.Status != "voting" {
+		return nil, errors.New("当前不在评审阶段")
+	}
+
+	criteria, err := s.GetCriteria(submission.HackathonID)
+	if err != nil {
+		return nil, err
+	}
+	if len(criteria) == 0 {
+		return nil, errors.New("活动尚未设置评分标准")
+	}
+
+	values := make(map[uint64]float64)
+	for _, input := range inputs {
+		values[input.CriterionID] = input.Score
+	}
+
+	items := make([]models.JudgeScoreItem, 0, len(criteria))
+	for _, criterion := range criteria {
+		value, ok := values[criterion.ID]
+		if !ok {
+			return nil, fmt.Errorf("请为%s打分", criterion.Name)
+		}
+		if value < float64(criterion.MinScore) || value > float64(criterion.MaxScore) {
+			return nil, fmt.Errorf("%s的得分必须在%d~%d之间", criterion.Name, criterion.MinScore, criterion.MaxScore)
+		}
+		items = append(items, models.JudgeScoreItem{CriterionID: criterion.ID, Score: value})
+	}
+
+	score := models.JudgeScore{
+		HackathonID:  submission.HackathonID,
+		SubmissionID: submission.ID,
+		JudgeID:      judgeID,
+		Total:        weightedTotal(criteria, items),
+		Comment:      strings.TrimSpace(comment),
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var existing models.JudgeScore
+		if err := tx.Where("submission_id = ? AND judge_id = ?", submission.ID, judgeID).First(&existing).Error; err == nil {
+			score.ID = existing.ID
+			score.CreatedAt = existing.CreatedAt
+			if err := tx.Where("score_id = ?", existing.ID).Delete(&models.JudgeScoreItem{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit("Items", "Judge").Save(&score).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].ScoreID = score.ID
+			if err := tx.Create(&items[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	score.Items = items
+	return &score, nil
+}
+
+// GetScoreSummary 汇总活动作品的评委评分（主办方）
+// 平均分为各评委加权总分的算术平均；标准化分先将每位评委的打分换算为 z-score 再取平均
+func (s *JudgingService) GetScoreSummary(hackathonID uint64, sortBy string) ([]SubmissionJudgeSummary, error) {
+	var submissions []models.Submission
+	if err := database.DB.Preload("Team").
+		Where("hackathon_id = ? AND draft = 0 AND moderation_status IN ?", hackathonID, PublicModerationStatuses).
+		Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+
+	var scores []models.JudgeScore
+	if err := database.DB.Preload("Judge").Preload("Items").Where("hackathon_id = ?", hackathonID).Find(&scores).Error; err != nil {
+		return nil, err
+	}
+
+	normalized := normalizeJudgeScores(scores)
+
+	bySubmission := make(map[uint64][]models.JudgeScore)
+	for _, score := range scores {
+		bySubmission[score.SubmissionID] = append(bySubmission[score.SubmissionID], score)
+	}
+
+	summaries := make([]SubmissionJudgeSummary, 0, len(submissions))
+	for _, submission := range submissions {
+		summary := SubmissionJudgeSummary{
+			SubmissionID:   submission.ID,
+			SubmissionName: submission.Name,
+			TeamName:       submission.Team.Name,
+			CriterionMeans: make(map[uint64]float64),
+			Scores:         bySubmission[submission.ID],
+		}
+		if summary.Scores == nil {
+			summary.Scores = make([]models.JudgeScore, 0)
+		}
+
+		criterionSums := make(map[uint64]float64)
+		criterionCounts := make(map[uint64]int)
+		var totalSum, normalizedSum float64
+		for _, score := range summary.Scores {
+			totalSum += score.Total
+			normalizedSum += normalized[score.ID]
+			for _, item := range score.Items {
+				criterionSums[item.CriterionID] += item.Score
+				criterionCounts[item.CriterionID]++
+			}
+		}
+
+		summary.JudgeCount = len(summary.Scores)
+		if summary.JudgeCount > 0 {
+			summary.MeanScore = roundScore(totalSum / float64(summary.JudgeCount))
+			summary.NormalizedScore = roundScore(normalizedSum / float64(summary.JudgeCount))
+		}
+		for criterionID, sum := range criterionSums {
+			summary.CriterionMeans[criterionID] = roundScore(sum / float64(criterionCounts[criterionID]))
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if sortBy == "normalized" {
+			return summaries[i].NormalizedScore > summaries[j].NormalizedScore
+		}
+		return summaries[i].MeanScore > summaries[j].MeanScore
+	})
+
+	return summaries, nil
+}
+
+// checkOrganizer 检查活动是否存在且当前用户是活动创建者
+func (s *JudgingService) checkOrganizer(hackathonID, userID uint64, userRole string) (*models.Hackathon, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+	if userRole == "admin" {
+		return nil, errors.New("Admin不能设置活动评审")
+	}
+	if hackathon.OrganizerID != userID {
+		return nil, errors.New("只能设置自己创建的活动评审")
+	}
+	return &hackathon, nil
+}
+
+// loadJudgeSubmission 加载评委可评审的作品（已定稿且未被隐藏或取消资格、已分配给该评委且没有利益冲突）
+func (s *JudgingService) loadJudgeSubmission(submissionID, judgeID uint64) (*models.Submission, error) {
+	var submission models.Submission
+	if err := database.DB.Where("id = ? AND draft = 0 AND moderation_status IN ?", submissionID, PublicModerationStatuses).First(&submission).Error; err != nil {
+		return nil, errors.New("作品不存在")
+	}
+	if !s.IsJudge(submission.HackathonID, judgeID) {
+		return nil, errors.New("您不是该活动的评委")
+	}
+
+	assignmentService := &JudgeAssignmentService{}
+	if reason := assignmentService.ConflictReason(submission.HackathonID, submission.TeamID, judgeID); reason != "" {
+		return nil, errors.New("存在利益冲突，无法评审该作品：" + reason)
+	}
+	if !assignmentService.IsAssigned(submission.HackathonID, submission.ID, judgeID) {
+		return nil, errors.New("该作品未分配给您评审")
+	}
+	return &submission, nil
+}
+
+// weightedTotal 计算加权总分：各维度得分换算到 0~1 后按权重加权，再换算为 0~100
+func weightedTotal(criteria []models.JudgingCriterion, items []models.JudgeScoreItem) float64 {
+	values := make(map[uint64]float64)
+	for _, item := range items {
+		values[item.CriterionID] = item.Score
+	}
+
+	var weighted, weightSum float64
+	for _, criterion := range criteria {
+		span := float64(criterion.MaxScore - criterion.MinScore)
+		weighted += criterion.Weight * (values[criterion.ID] - float64(criterion.MinScore)) / span
+		weightSum += criterion.Weight
+	}
+	if weightSum == 0 {
+		return 0
+	}
+	return roundScore(weighted / weightSum * 100)
+}
+
+// normalizeJudgeScores 将每位评委的加权总分换算为 z-score（评委只评了一个作品或打分完全相同时记为0）
+func normalizeJudgeScores(scores []models.JudgeScore) map[uint64]float64 {
+	byJudge := make(map[uint64][]models.JudgeScore)
+	for _, score := range scores {
+		byJudge[score.JudgeID] = append(byJudge[score.JudgeID], score)
+	}
+
+	result := make(map[uint64]float64)
+	for _, judgeScores := range byJudge {
+		var sum float64
+		for _, score := range judgeScores {
+			sum += score.Total
+		}
+		mean := sum / float64(len(judgeScores))
+
+		var variance float64
+		for _, score := range judgeScores {
+			variance += (score.Total - mean) * (score.Total - mean)
+		}
+		stddev := math.Sqrt(variance / float64(len(judgeScores)))
+
+		for _, score := range judgeScores {
+			if stddev == 0 {
+				result[score.ID] = 0
+				continue
+			}
+			result[score.ID] = (score.Total - mean) / stddev
+		}
+	}
+	return result
+}
+
+// roundScore 分数保留两位小数
+func roundScore(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+	"hackathon-backend/utils"
+)
+
+// linkCheckWorkers 并发检查链接的数量
+const linkCheckWorkers = 8
+
+// linkCheckFields 需要检查的作品链接字段
+var linkCheckFields = []struct {
+	Field string
+	Label string
+	Value func(*models.Submission) string
+}{
+	{"link", "作品链接", func(s *models.Submission) string { return s.Link }},
+	{"repo_url", "代码仓库地址", func(s *models.Submission) string { return s.RepoURL }},
+	{"demo_url", "演示地址", func(s *models.Submission) string { return s.DemoURL }},
+	{"video_url", "演示视频地址", func(s *models.Submission) string { return s.VideoURL }},
+	{"cover_image", "封面图片", func(s *models.Submission) string { return s.CoverImage }},
+}
+
+type LinkCheckService struct{}
+
+// LinkProbeResult 单次链接探测结果
+type LinkProbeResult struct {
+	StatusCode int
+	LatencyMs  int64
+	Error      string
+}
+
+// Healthy 状态码为 2xx/3xx 视为正常
+func (r *LinkProbeResult) Healthy() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 400
+}
+
+// linkCheckTask 待检查的链接
+type linkCheckTask struct {
+	submission *models.Submission
+	field      string
+	label      string
+	url        string
+}
+
+// RunDueChecks 检查提交和投票阶段活动中到期的作品链接（定时任务）
+func (s *LinkCheckService) RunDueChecks() error {
+	var submissions []models.Submission
+	if err := database.DB.Joins("JOIN hackathons ON hackathons.id = submissions.hackathon_id").
+		Where("hackathons.status IN ? AND hackathons.deleted_at IS NULL", []string{"submission", "voting"}).
+		Preload("LinkChecks").
+		Find(&submissions).Error; err != nil {
+		return err
+	}
+
+	interval := time.Duration(config.AppConfig.LinkCheckIntervalMin) * time.Minute
+	s.checkSubmissions(submissions, func(check *models.SubmissionLinkCheck, url string) bool {
+		return check == nil || check.URL != url || time.Since(check.LastCheckedAt) >= interval
+	})
+	return nil
+}
+
+// CheckHackathon 立即检查活动中所有作品的链接（主办方手动触发）
+func (s *LinkCheckService) CheckHackathon(hackathonID uint64) (int, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return 0, errors.New("活动不存在")
+	}
+
+	var submissions []models.Submission
+	if err := database.DB.Where("hackathon_id = ?", hackathonID).Preload("LinkChecks").Find(&submissions).Error; err != nil {
+		return 0, err
+	}
+
+	return s.checkSubmissions(submissions, func(*models.SubmissionLinkCheck, string) bool { return true }), nil
+}
+
+// GetBrokenLinks 获取活动中无法访问的作品链接
+func (s *LinkCheckService) GetBrokenLinks(hackathonID uint64) ([]map[string]interface{}, error) {
+	var checks []models.SubmissionLinkCheck
+	if err := database.DB.Joins("JOIN submissions ON submissions.id = submission_link_checks.submission_id").
+		Where("submissions.hackathon_id = ? AND submission_link_checks.healthy = ?", hackathonID, false).
+		Order("submission_link_checks.fail_count DESC").
+		Find(&checks).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(checks))
+	for _, check := range checks {
+		var submission models.Submission
+		if err := database.DB.Preload("Team").Where("id = ?", check.SubmissionID).First(&submission).Error; err != nil {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"submission_id":   submission.ID,
+			"submission_name": submission.Name,
+			"team_id":         submission.TeamID,
+			"team_name":       submission.Team.Name,
+			"draft":           submission.Draft,
+			"check":           check,
+		})
+	}
+	return result, nil
+}
+
+// ProbeLink 请求链接并记录状态码和耗时（先尝试 HEAD，失败时改用 GET，部分站点不支持 HEAD）
+func (s *LinkCheckService) ProbeLink(client *http.Client, url string) *LinkProbeResult {
+	result := s.probe(client, http.MethodHead, url)
+	if result.Healthy() {
+		return result
+	}
+	return s.probe(client, http.MethodGet, url)
+}
+
+func (s *LinkCheckService) probe(client *http.Client, method, url string) *LinkProbeResult {
+	req, err := http.NewRequestWithContext(context.Background(), method, url, nil)
+	if err != nil {
+		return &LinkProbeResult{Error: "无效的链接"}
+	}
+	req.Header.Set("User-Agent", "HackathonLinkChecker/1.0")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		message := err.Error()
+		if len(message) > 255 {
+			message = message[:255]
+		}
+		return &LinkProbeResult{LatencyMs: latency, Error: message}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return &LinkProbeResult{StatusCode: resp.StatusCode, LatencyMs: latency}
+}
+
+// checkSubmissions 并发检查作品链接，返回检查的链接数量
+func (s *LinkCheckService) checkSubmissions(submissions []models.Submission, due func(*models.SubmissionLinkCheck, string) bool) int {
+	tasks := make(chan linkCheckTask)
+	var wg sync.WaitGroup
+
+	timeout := time.Duration(config.AppConfig.LinkCheckTimeoutSec) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	// 链接由参赛者填写，默认禁止访问内网地址（含重定向后的地址）
+	client := utils.NewPublicHTTPClient(timeout, config.AppConfig.AllowPrivateNetwork)
+
+	for i := 0; i < linkCheckWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range tasks {
+				s.checkLink(client, task)
+			}
+		}()
+	}
+
+	count := 0
+	for i := range submissions {
+		submission := &submissions[i]
+		existing := make(map[string]*models.SubmissionLinkCheck)
+		for j := range submission.LinkChecks {
+			existing[submission.LinkChecks[j].Field] = &submission.LinkChecks[j]
+		}
+
+		for _, field := range linkCheckFields {
+			url := field.Value(submission)
+			if url == "" {
+				// 链接已清空时删除旧的检查记录
+				if existing[field.Field] != nil {
+					database.DB.Delete(existing[field.Field])
+				}
+				continue
+			}
+			if !due(existing[field.Field], url) {
+				continue
+			}
+			tasks <- linkCheckTask{submission: submission, field: field.Field, label: field.Label, url: url}
+			count++
+		}
+	}
+	close(tasks)
+	wg.Wait()
+
+	return count
+}
+
+// checkLink 检查单个链接、保存结果，链接变为不可访问时通知队伍
+func (s *LinkCheckService) checkLink(client *http.Client, task linkCheckTask) {
+	result := s.ProbeLink(client, task.url)
+
+	// 尚未检查过时 check 为空记录（ID 为 0）
+	var check models.SubmissionLinkCheck
+	database.DB.Where("submission_id = ? AND field = ?", task.submission.ID, task.field).First(&check)
+	event := applyLinkProbe(&check, task, result, time.Now())
+
+	if err := database.DB.Save(&check).Error; err != nil {
+		log.Printf("保存作品 %d 的链接检查结果失败: %v", task.submission.ID, err)
+		return
+	}
+
+	// 推送队伍频道系统事件（仅在状态变化时提醒）
+	if event != "" {
+		channelService := &TeamChannelService{}
+		channelService.PublishSystemEvent(task.submission.TeamID, event)
+	}
+}
+
+// applyLinkProbe 将探测结果写入检查记录（ID 为 0 表示首次检查），返回需要通知队伍的消息
+// 链接变为不可访问或恢复访问时返回消息，状态未变化时返回空字符串；更换链接后按首次检查处理
+func applyLinkProbe(check *models.SubmissionLinkCheck, task linkCheckTask, result *LinkProbeResult, now time.Time) string {
+	sameURL := check.ID != 0 && check.URL == task.url
+	wasHealthy := !sameURL || check.Healthy
+
+	failCount := 0
+	if !result.Healthy() {
+		failCount = 1
+		if sameURL {
+			failCount = check.FailCount + 1
+		}
+	}
+
+	check.SubmissionID = task.submission.ID
+	check.Field = task.field
+	check.URL = task.url
+	check.Healthy = result.Healthy()
+	check.StatusCode = result.StatusCode
+	check.LatencyMs = result.LatencyMs
+	check.Error = result.Error
+	check.FailCount = failCount
+	check.LastCheckedAt = now
+
+	if wasHealthy && !check.Healthy {
+		reason := result.Error
+		if result.StatusCode != 0 {
+			reason = fmt.Sprintf("状态码 %d", result.StatusCode)
+		}
+		return fmt.Sprintf("%s无法访问（%s）：%s", task.label, reason, task.url)
+	}
+	if !wasHealthy && check.Healthy {
+		return fmt.Sprintf("%s已恢复访问：%s", task.label, task.url)
+	}
+	return ""
+}
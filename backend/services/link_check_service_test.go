@@ -0,0 +1,139 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hackathon-backend/models"
+	"hackathon-backend/utils"
+)
+
+// probeMethods 探测测试服务器并返回服务器收到的请求方法
+func probeMethods(t *testing.T, headStatus int) (*LinkProbeResult, string) {
+	t.Helper()
+	var mu sync.Mutex
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		methods = append(methods, r.Method)
+		mu.Unlock()
+		if r.Header.Get("User-Agent") != "HackathonLinkChecker/1.0" {
+			t.Errorf("User-Agent 错误: %q", r.Header.Get("User-Agent"))
+		}
+		if r.Method == http.MethodHead {
+			w.WriteHeader(headStatus)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	result := (&LinkCheckService{}).ProbeLink(utils.NewPublicHTTPClient(time.Second, true), server.URL)
+	mu.Lock()
+	defer mu.Unlock()
+	return result, strings.Join(methods, ",")
+}
+
+func TestProbeLinkFallsBackToGet(t *testing.T) {
+	result, methods := probeMethods(t, http.StatusMethodNotAllowed)
+	if !result.Healthy() || result.StatusCode != http.StatusOK {
+		t.Fatalf("探测结果错误: %+v", result)
+	}
+	if methods != "HEAD,GET" {
+		t.Fatalf("请求方法为 %s，期望先 HEAD 后 GET", methods)
+	}
+
+	// HEAD 成功时不再发送 GET
+	result, methods = probeMethods(t, http.StatusOK)
+	if !result.Healthy() || methods != "HEAD" {
+		t.Fatalf("探测结果错误: %+v，请求方法为 %s", result, methods)
+	}
+}
+
+func TestProbeLinkTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	result := (&LinkCheckService{}).ProbeLink(utils.NewPublicHTTPClient(100*time.Millisecond, true), server.URL)
+	if result.Healthy() || result.StatusCode != 0 || result.Error == "" {
+		t.Fatalf("超时应视为无法访问: %+v", result)
+	}
+}
+
+func TestProbeLinkRejectsPrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("不应请求内网地址")
+	}))
+	defer server.Close()
+
+	client := utils.NewPublicHTTPClient(time.Second, false)
+	result := (&LinkCheckService{}).ProbeLink(client, server.URL)
+	if result.Healthy() || !strings.Contains(result.Error, utils.ErrPrivateAddress.Error()) {
+		t.Fatalf("内网地址应被拒绝: %+v", result)
+	}
+
+	if _, err := client.Get("http://169.254.169.254/latest/meta-data"); !errors.Is(err, utils.ErrPrivateAddress) {
+		t.Fatalf("错误应为 ErrPrivateAddress，实际为 %v", err)
+	}
+}
+
+func TestApplyLinkProbeAlertsOnStateChange(t *testing.T) {
+	task := linkCheckTask{
+		submission: &models.Submission{ID: 7, TeamID: 3},
+		field:      "demo_url",
+		label:      "演示地址",
+		url:        "https://demo.example.com",
+	}
+	healthy := &LinkProbeResult{StatusCode: 200}
+	notFound := &LinkProbeResult{StatusCode: 404}
+	timedOut := &LinkProbeResult{Error: "timeout"}
+	now := time.Now()
+
+	check := models.SubmissionLinkCheck{}
+	if event := applyLinkProbe(&check, task, healthy, now); event != "" {
+		t.Fatalf("首次检查正常时不应提醒: %q", event)
+	}
+	check.ID = 1
+
+	event := applyLinkProbe(&check, task, notFound, now)
+	if event != "演示地址无法访问（状态码 404）：https://demo.example.com" {
+		t.Fatalf("变为无法访问时的提醒错误: %q", event)
+	}
+	if check.Healthy || check.FailCount != 1 || check.SubmissionID != 7 || check.Field != "demo_url" {
+		t.Fatalf("检查记录错误: %+v", check)
+	}
+
+	if event := applyLinkProbe(&check, task, timedOut, now); event != "" {
+		t.Fatalf("持续无法访问时不应重复提醒: %q", event)
+	}
+	if check.FailCount != 2 || check.Error != "timeout" {
+		t.Fatalf("连续失败次数错误: %+v", check)
+	}
+
+	if event := applyLinkProbe(&check, task, healthy, now); event != "演示地址已恢复访问：https://demo.example.com" {
+		t.Fatalf("恢复访问时的提醒错误: %q", event)
+	}
+	if !check.Healthy || check.FailCount != 0 {
+		t.Fatalf("恢复后检查记录错误: %+v", check)
+	}
+
+	// 更换链接后按首次检查处理，失败时重新提醒
+	applyLinkProbe(&check, task, notFound, now)
+	task.url = "https://demo2.example.com"
+	event = applyLinkProbe(&check, task, timedOut, now)
+	if event != "演示地址无法访问（timeout）：https://demo2.example.com" || check.FailCount != 1 {
+		t.Fatalf("更换链接后的提醒错误: %q %+v", event, check)
+	}
+}
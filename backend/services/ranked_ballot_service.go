@@ -0,0 +1,274 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type RankedBallotService struct{}
+
+// RankedTally 排序投票计票结果
+type RankedTally struct {
+	SubmissionID uint64 `json:"submission_id"`
+	Score        int64  `json:"score"` // 波达计数为总分；即时决选为最后所在轮次的票数
+	Round        int    `json:"round"` // 即时决选中被淘汰或决出名次的轮次（波达计数为0）
+}
+
+// SubmitBallot 提交排序选票（整体替换已有选票，投票期间可修改）
+// submissionIDs 按排名从高到低排列
+func (s *RankedBallotService) SubmitBallot(hackathonID, participantID uint64, submissionIDs []uint64, fingerprint VoteFingerprint) (*models.RankedBallot, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+
+	if hackathon.VotingMode != "ranked" {
+		return nil, errors.New("本活动未采用排序投票")
+	}
+
+	// 检查投票资格
+	voteService := &VoteService{}
+	if err := voteService.checkVoter(&hackathon, participantID); err != nil {
+		return nil, err
+	}
+
+	if len(submissionIDs) == 0 {
+		return nil, errors.New("请至少选择一个作品")
+	}
+	if len(submissionIDs) > hackathon.RankedBallotSize {
+		return nil, fmt.Errorf("最多只能排序%d个作品", hackathon.RankedBallotSize)
+	}
+
+	teamService := &TeamService{}
+	team, _ := teamService.GetUserTeam(hackathonID, participantID)
+
+	seen := make(map[uint64]bool)
+	for _, submissionID := range submissionIDs {
+		if seen[submissionID] {
+			return nil, fmt.Errorf("作品 %d 重复排序", submissionID)
+		}
+		seen[submissionID] = true
+
+		if _, err := voteService.loadVoteSubmission(&hackathon, team, submissionID); err != nil {
+			return nil, fmt.Errorf("作品 %d: %w", submissionID, err)
+		}
+	}
+
+	fingerprint = fingerprint.normalize()
+	voided := (&VoteFraudService{}).isVoterVoided(hackathonID, participantID)
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		ballot := models.RankedBallot{HackathonID: hackathonID, ParticipantID: participantID}
+		if err := tx.Where("hackathon_id = ? AND participant_id = ?", hackathonID, participantID).
+			FirstOrCreate(&ballot).Error; err != nil {
+			return fmt.Errorf("保存选票失败: %w", err)
+		}
+
+		if err := tx.Where("ballot_id = ?", ballot.ID).Delete(&models.RankedBallotItem{}).Error; err != nil {
+			return err
+		}
+
+		for i, submissionID := range submissionIDs {
+			item := models.RankedBallotItem{
+				BallotID:     ballot.ID,
+				SubmissionID: submissionID,
+				Rank:         i + 1,
+			}
+			if err := tx.Create(&item).Error; err != nil {
+				return fmt.Errorf("保存选票失败: %w", err)
+			}
+		}
+
+		return tx.Model(&ballot).Updates(map[string]interface{}{
+			"voided":     voided,
+			"client_ip":  fingerprint.ClientIP,
+			"user_agent": fingerprint.UserAgent,
+			"updated_at": time.Now(),
+		}).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	(&VoteLeaderboardService{}).Publish(hackathonID)
+	return s.GetMyBallot(hackathonID, participantID)
+}
+
+// CancelBallot 撤回排序选票
+func (s *RankedBallotService) CancelBallot(hackathonID, participantID uint64) error {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+
+	var ballot models.RankedBallot
+	if err := database.DB.Where("hackathon_id = ? AND participant_id = ?", hackathonID, participantID).First(&ballot).Error; err != nil {
+		return errors.New("选票不存在")
+	}
+
+	voteService := &VoteService{}
+	if err := voteService.checkVoter(&hackathon, participantID); err != nil {
+		return err
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ballot_id = ?", ballot.ID).Delete(&models.RankedBallotItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&ballot).Error
+	}); err != nil {
+		return err
+	}
+
+	(&VoteLeaderboardService{}).Publish(hackathonID)
+	return nil
+}
+
+// GetMyBallot 获取我的排序选票（尚未投票时返回 nil）
+func (s *RankedBallotService) GetMyBallot(hackathonID, participantID uint64) (*models.RankedBallot, error) {
+	var ballot models.RankedBallot
+	err := database.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("`rank` ASC")
+	}).Where("hackathon_id = ? AND participant_id = ?", hackathonID, participantID).First(&ballot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	submissionIDs := make([]uint64, 0, len(ballot.Items))
+	for _, item := range ballot.Items {
+		submissionIDs = append(submissionIDs, item.SubmissionID)
+	}
+
+	var submissions []models.Submission
+	if len(submissionIDs) > 0 {
+		if err := database.DB.Preload("Team").Where("id IN ?", submissionIDs).Find(&submissions).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	byID := make(map[uint64]*models.Submission, len(submissions))
+	for i := range submissions {
+		byID[submissions[i].ID] = &submissions[i]
+	}
+	for i := range ballot.Items {
+		ballot.Items[i].Submission = byID[ballot.Items[i].SubmissionID]
+	}
+
+	return &ballot, nil
+}
+
+// Tally 统计排序选票，返回按名次排列的计票结果
+// 只统计 submissionIDs 中的作品，选票中的其他作品（如已隐藏）被跳过，后面的作品依次前移；作废的选票不参与计票
+func (s *RankedBallotService) Tally(hackathon *models.Hackathon, submissionIDs []uint64) ([]RankedTally, error) {
+	var ballots []models.RankedBallot
+	if err := database.DB.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("`rank` ASC")
+	}).Where("hackathon_id = ? AND voided = ?", hackathon.ID, false).Find(&ballots).Error; err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[uint64]bool, len(submissionIDs))
+	for _, id := range submissionIDs {
+		candidates[id] = true
+	}
+
+	rankings := make([][]uint64, 0, len(ballots))
+	for _, ballot := range ballots {
+		ranking := make([]uint64, 0, len(ballot.Items))
+		for _, item := range ballot.Items {
+			if candidates[item.SubmissionID] {
+				ranking = append(ranking, item.SubmissionID)
+			}
+		}
+		if len(ranking) > 0 {
+			rankings = append(rankings, ranking)
+		}
+	}
+
+	if hackathon.RankedTallyMethod == "irv" {
+		return instantRunoffTally(rankings, submissionIDs), nil
+	}
+	return bordaTally(rankings, submissionIDs, hackathon.RankedBallotSize), nil
+}
+
+// bordaTally 波达计数：选票中第 i 名（从1开始）得 size-i+1 分，按总分降序排列
+func bordaTally(rankings [][]uint64, candidates []uint64, size int) []RankedTally {
+	scores := make(map[uint64]int64, len(candidates))
+	for _, ranking := range rankings {
+		for i, id := range ranking {
+			if points := size - i; points > 0 {
+				scores[id] += int64(points)
+			}
+		}
+	}
+
+	tallies := make([]RankedTally, 0, len(candidates))
+	for _, id := range candidates {
+		tallies = append(tallies, RankedTally{SubmissionID: id, Score: scores[id]})
+	}
+	sort.SliceStable(tallies, func(i, j int) bool {
+		if tallies[i].Score != tallies[j].Score {
+			return tallies[i].Score > tallies[j].Score
+		}
+		return tallies[i].SubmissionID < tallies[j].SubmissionID
+	})
+	return tallies
+}
+
+// instantRunoffTally 即时决选：每轮统计各选票中排名最高的未淘汰作品，
+// 有作品获得过半数有效票（或只剩一个作品）时决出名次，否则淘汰票数最少的作品（票数相同时淘汰ID较大的作品）。
+// 决出名次时剩余作品按本轮票数排列，已淘汰的作品按淘汰顺序倒序排在其后
+func instantRunoffTally(rankings [][]uint64, candidates []uint64) []RankedTally {
+	remaining := make(map[uint64]bool, len(candidates))
+	for _, id := range candidates {
+		remaining[id] = true
+	}
+
+	var eliminated []RankedTally
+	for round := 1; len(remaining) > 0; round++ {
+		counts := make(map[uint64]int64, len(remaining))
+		var active int64
+		for _, ranking := range rankings {
+			for _, id := range ranking {
+				if remaining[id] {
+					counts[id]++
+					active++
+					break
+				}
+			}
+		}
+
+		current := make([]RankedTally, 0, len(remaining))
+		for id := range remaining {
+			current = append(current, RankedTally{SubmissionID: id, Score: counts[id], Round: round})
+		}
+		sort.Slice(current, func(i, j int) bool {
+			if current[i].Score != current[j].Score {
+				return current[i].Score > current[j].Score
+			}
+			return current[i].SubmissionID < current[j].SubmissionID
+		})
+
+		if len(current) == 1 || active == 0 || current[0].Score*2 > active {
+			for i := len(eliminated) - 1; i >= 0; i-- {
+				current = append(current, eliminated[i])
+			}
+			return current
+		}
+
+		loser := current[len(current)-1]
+		eliminated = append(eliminated, loser)
+		delete(remaining, loser.SubmissionID)
+	}
+
+	return eliminated
+}
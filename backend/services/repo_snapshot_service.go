@@ -0,0 +1,266 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+	"hackathon-backend/utils"
+)
+
+type RepoSnapshotService struct{}
+
+// RepoSnapshot 代码仓库快照结果
+type RepoSnapshot struct {
+	HeadCommit         string
+	CommitCount        int
+	CommitsBeforeStart int
+	CommitsAfterEnd    int
+}
+
+// Verdict 根据提交时间得出检查结果：verified-全部提交在活动时间范围内，outside_window-存在范围外的提交
+func (s *RepoSnapshot) Verdict() (string, string) {
+	if s.CommitsBeforeStart == 0 && s.CommitsAfterEnd == 0 {
+		return "verified", fmt.Sprintf("共%d次提交，均在活动时间范围内", s.CommitCount)
+	}
+	return "outside_window", fmt.Sprintf("共%d次提交，其中%d次早于活动开始，%d次晚于提交截止",
+		s.CommitCount, s.CommitsBeforeStart, s.CommitsAfterEnd)
+}
+
+// RunDueSnapshots 为提交阶段已截止、尚未在截止后记录快照的作品记录仓库快照（定时任务）
+func (s *RepoSnapshotService) RunDueSnapshots() error {
+	var stages []models.HackathonStage
+	if err := database.DB.Joins("JOIN hackathons ON hackathons.id = hackathon_stages.hackathon_id").
+		Where("hackathon_stages.stage = ? AND hackathon_stages.end_time <= ? AND hackathons.deleted_at IS NULL", "submission", time.Now()).
+		Find(&stages).Error; err != nil {
+		return err
+	}
+
+	for _, stage := range stages {
+		var submissions []models.Submission
+		if err := database.DB.Where("hackathon_id = ? AND repo_url <> '' AND (repo_snapshot_at IS NULL OR repo_snapshot_at < ?)", stage.HackathonID, stage.EndTime).
+			Find(&submissions).Error; err != nil {
+			return err
+		}
+		if len(submissions) == 0 {
+			continue
+		}
+
+		var hackathon models.Hackathon
+		if err := database.DB.Where("id = ?", stage.HackathonID).First(&hackathon).Error; err != nil {
+			continue
+		}
+		for i := range submissions {
+			s.snapshotSubmission(&submissions[i], hackathon.StartTime, stage.EndTime)
+		}
+	}
+	return nil
+}
+
+// SnapshotHackathon 立即为活动中所有填写了仓库地址的作品记录仓库快照（主办方手动触发）
+func (s *RepoSnapshotService) SnapshotHackathon(hackathonID uint64) (int, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return 0, errors.New("活动不存在")
+	}
+
+	var stage models.HackathonStage
+	if err := database.DB.Where("hackathon_id = ? AND stage = ?", hackathonID, "submission").First(&stage).Error; err != nil {
+		return 0, errors.New("提交阶段时间未设置")
+	}
+
+	var submissions []models.Submission
+	if err := database.DB.Where("hackathon_id = ? AND repo_url <> ''", hackathonID).Find(&submissions).Error; err != nil {
+		return 0, err
+	}
+
+	for i := range submissions {
+		s.snapshotSubmission(&submissions[i], hackathon.StartTime, stage.EndTime)
+	}
+	return len(submissions), nil
+}
+
+// GetSnapshotList 获取活动作品的仓库快照结果
+func (s *RepoSnapshotService) GetSnapshotList(hackathonID uint64, status string) ([]models.Submission, error) {
+	query := database.DB.Where("hackathon_id = ? AND repo_url <> ''", hackathonID)
+	if status != "" {
+		query = query.Where("repo_check_status = ?", status)
+	}
+
+	var submissions []models.Submission
+	if err := query.Preload("Team").Order("id ASC").Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+// snapshotSubmission 记录单个作品的仓库快照并保存结果
+func (s *RepoSnapshotService) snapshotSubmission(submission *models.Submission, windowStart, windowEnd time.Time) {
+	now := time.Now()
+	updates := map[string]interface{}{"repo_snapshot_at": &now}
+
+	snapshot, err := InspectGitRepository(submission.RepoURL, windowStart, windowEnd)
+	if err != nil {
+		message := err.Error()
+		if len(message) > 500 {
+			message = message[:500]
+		}
+		updates["repo_check_status"] = "error"
+		updates["repo_check_message"] = message
+	} else {
+		updates["repo_head_commit"] = snapshot.HeadCommit
+		updates["repo_commit_count"] = snapshot.CommitCount
+		updates["repo_commits_before_start"] = snapshot.CommitsBeforeStart
+		updates["repo_commits_after_end"] = snapshot.CommitsAfterEnd
+		updates["repo_check_status"], updates["repo_check_message"] = snapshot.Verdict()
+	}
+
+	if err := database.DB.Model(submission).UpdateColumns(updates).Error; err != nil {
+		log.Printf("保存作品 %d 的仓库快照失败: %v", submission.ID, err)
+	}
+}
+
+// InspectGitRepository 克隆仓库并统计提交时间（按提交者时间）是否落在 [windowStart, windowEnd] 范围内
+// 支持公网 http/https 仓库，配置允许时支持 file:// 本地仓库和内网地址
+func InspectGitRepository(repoURL string, windowStart, windowEnd time.Time) (*RepoSnapshot, error) {
+	allowProtocols := "http:https"
+	if config.AppConfig.GitAllowFileURL {
+		allowProtocols += ":file"
+	}
+
+	timeout := time.Duration(config.AppConfig.GitCloneTimeoutSec) * time.Second
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	gitConfig, err := gitRemoteGuard(ctx, repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "repo-snapshot-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	env := append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_ALLOW_PROTOCOL="+allowProtocols,
+	)
+
+	// 只需要提交记录，不下载文件内容
+	args := append(gitConfig, "clone", "--bare", "--quiet", "--filter=blob:none", "--", repoURL, dir)
+	if _, err := runGit(ctx, env, args...); err != nil {
+		return nil, fmt.Errorf("克隆仓库失败: %w", err)
+	}
+
+	head, err := runGit(ctx, env, "-C", dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, errors.New("仓库没有任何提交")
+	}
+
+	output, err := runGit(ctx, env, "-C", dir, "log", "--format=%ct", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("读取提交记录失败: %w", err)
+	}
+
+	snapshot := &RepoSnapshot{HeadCommit: strings.TrimSpace(head)}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		ts, err := strconv.ParseInt(strings.TrimSpace(scanner.Text()), 10, 64)
+		if err != nil {
+			continue
+		}
+		committedAt := time.Unix(ts, 0)
+		snapshot.CommitCount++
+		if committedAt.Before(windowStart) {
+			snapshot.CommitsBeforeStart++
+		} else if committedAt.After(windowEnd) {
+			snapshot.CommitsAfterEnd++
+		}
+	}
+	return snapshot, nil
+}
+
+// gitRemoteGuard 检查仓库地址，禁止克隆内网地址的仓库，返回克隆时需要的 git 配置参数
+// git 自行解析域名，因此将域名固定到检查过的地址，并禁止重定向到其他地址
+func gitRemoteGuard(ctx context.Context, repoURL string) ([]string, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, errors.New("无效的仓库地址")
+	}
+	if u.Scheme == "file" {
+		if !config.AppConfig.GitAllowFileURL {
+			return nil, errors.New("不支持的仓库地址")
+		}
+		return nil, nil
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.New("仓库地址只支持 http 或 https")
+	}
+	if config.AppConfig.AllowPrivateNetwork {
+		return nil, nil
+	}
+
+	host := u.Hostname()
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	ip := net.ParseIP(host)
+	if ip != nil {
+		if !utils.IsPublicIP(ip) {
+			return nil, utils.ErrPrivateAddress
+		}
+		return []string{"-c", "http.followRedirects=false"}, nil
+	}
+
+	ip, err = utils.ResolvePublicHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	resolved := ip.String()
+	if ip.To4() == nil {
+		resolved = "[" + resolved + "]"
+	}
+	return []string{
+		"-c", "http.followRedirects=false",
+		"-c", "http.curloptResolve=" + host + ":" + port + ":" + resolved,
+	}, nil
+}
+
+// runGit 执行 git 命令并返回标准输出
+func runGit(ctx context.Context, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
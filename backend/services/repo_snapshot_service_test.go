@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/utils"
+)
+
+// newTestRepo 创建本地 git 仓库，按给定的时间依次提交
+func newTestRepo(t *testing.T, commitTimes ...time.Time) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("未安装 git")
+	}
+
+	dir := t.TempDir()
+	git := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v 失败: %v\n%s", args, err, output)
+		}
+	}
+
+	git(nil, "init", "--quiet")
+	for _, at := range commitTimes {
+		if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(at.String()), 0644); err != nil {
+			t.Fatalf("写入文件失败: %v", err)
+		}
+		date := at.Format(time.RFC3339)
+		git(nil, "add", "file.txt")
+		git([]string{
+			"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date,
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		}, "commit", "--quiet", "-m", "commit at "+date)
+	}
+	return "file://" + dir
+}
+
+func TestInspectGitRepositoryCommitWindow(t *testing.T) {
+	config.AppConfig = &config.Config{GitAllowFileURL: true, GitCloneTimeoutSec: 30}
+
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 5, 3, 18, 0, 0, 0, time.UTC)
+
+	inside := newTestRepo(t, start.Add(time.Hour), start.Add(24*time.Hour), end.Add(-time.Minute))
+	snapshot, err := InspectGitRepository(inside, start, end)
+	if err != nil {
+		t.Fatalf("检查仓库失败: %v", err)
+	}
+	if snapshot.CommitCount != 3 || snapshot.CommitsBeforeStart != 0 || snapshot.CommitsAfterEnd != 0 {
+		t.Fatalf("提交统计错误: %+v", snapshot)
+	}
+	if len(snapshot.HeadCommit) != 40 {
+		t.Fatalf("HEAD 提交错误: %q", snapshot.HeadCommit)
+	}
+	if status, _ := snapshot.Verdict(); status != "verified" {
+		t.Fatalf("检查结果为 %s，期望 verified", status)
+	}
+
+	outside := newTestRepo(t, start.Add(-48*time.Hour), start.Add(time.Hour), end.Add(time.Hour), end.Add(2*time.Hour))
+	snapshot, err = InspectGitRepository(outside, start, end)
+	if err != nil {
+		t.Fatalf("检查仓库失败: %v", err)
+	}
+	if snapshot.CommitCount != 4 || snapshot.CommitsBeforeStart != 1 || snapshot.CommitsAfterEnd != 2 {
+		t.Fatalf("提交统计错误: %+v", snapshot)
+	}
+	status, message := snapshot.Verdict()
+	if status != "outside_window" || message != "共4次提交，其中1次早于活动开始，2次晚于提交截止" {
+		t.Fatalf("检查结果错误: %s %s", status, message)
+	}
+}
+
+func TestInspectGitRepositoryRejectsFileURLByDefault(t *testing.T) {
+	config.AppConfig = &config.Config{GitCloneTimeoutSec: 30}
+
+	repo := newTestRepo(t, time.Now())
+	if _, err := InspectGitRepository(repo, time.Now().Add(-time.Hour), time.Now().Add(time.Hour)); err == nil {
+		t.Fatal("未开启时不应允许 file:// 仓库")
+	}
+}
+
+func TestInspectGitRepositoryRejectsPrivateAddress(t *testing.T) {
+	config.AppConfig = &config.Config{GitCloneTimeoutSec: 30}
+
+	for _, repoURL := range []string{
+		"http://127.0.0.1/team/project.git",
+		"https://10.0.0.8/team/project.git",
+		"http://[::1]:8080/team/project.git",
+		"http://169.254.169.254/latest/meta-data",
+		"http://localhost/team/project.git",
+	} {
+		_, err := InspectGitRepository(repoURL, time.Now().Add(-time.Hour), time.Now())
+		if !errors.Is(err, utils.ErrPrivateAddress) {
+			t.Errorf("%s 应被拒绝，实际错误: %v", repoURL, err)
+		}
+	}
+
+	if _, err := InspectGitRepository("ssh://example.com/team/project.git", time.Now().Add(-time.Hour), time.Now()); err == nil {
+		t.Error("不应允许 ssh 仓库地址")
+	}
+}
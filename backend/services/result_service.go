@@ -0,0 +1,889 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// defaultTieBreakRules 可用的并列规则及未设置时使用的默认顺序：
+// judge_score 评委评分高者优先，vote_count 得票数多者优先，earliest_finalized 定稿时间早者优先，organizer_decision 主办方裁定
+var defaultTieBreakRules = []string{"judge_score", "vote_count", "earliest_finalized", "organizer_decision"}
+
+// scoringRuleColumns 活动表中的评分规则字段（仅通过评分规则接口修改）
+var scoringRuleColumns = []string{"judge_weight", "tie_break_rules"}
+
+type ResultService struct{}
+
+// ScoringRules 活动最终得分计算规则
+type ScoringRules struct {
+	JudgeWeight   int      `json:"judge_weight"`    // 评委评分权重（百分比）
+	VoteWeight    int      `json:"vote_weight"`     // 投票权重（百分比，等于 100 - judge_weight）
+	TieBreakRules []string `json:"tie_break_rules"` // 最终得分相同时依次使用的并列规则
+}
+
+// RankingBreakdown 作品最终得分明细
+type RankingBreakdown struct {
+	JudgeScore      float64 `json:"judge_score"`         // 评委平均分（0-100）
+	JudgeCount      int     `json:"judge_count"`         // 评分评委人数
+	VoteCount       int64   `json:"vote_count"`          // 得票数（排序投票为计票得分）
+	NormalizedVotes float64 `json:"normalized_votes"`    // 归一化投票得分（0-100）
+	JudgeWeight     int     `json:"judge_weight"`        // 评委评分权重（百分比）
+	VoteWeight      int     `json:"vote_weight"`         // 投票权重（百分比）
+	FinalScore      float64 `json:"final_score"`         // 最终得分
+	TieBreak        string  `json:"tie_break,omitempty"` // 与前一名最终得分相同时决定先后的规则
+}
+
+// RankedSubmission 排名中的作品
+type RankedSubmission struct {
+	Rank       int               `json:"rank"`
+	Submission models.Submission `json:"submission"`
+	Breakdown  RankingBreakdown  `json:"breakdown"`
+}
+
+// ResultEntry 已公布比赛结果中的作品（名次、得分明细、所获奖项和特别提名）
+type ResultEntry struct {
+	RankedSubmission
+	Award          *models.HackathonAward `json:"award"`
+	SpecialMention string                 `json:"special_mention"`
+}
+
+// ResultDraft 主办方编辑中的结果草稿
+type ResultDraft struct {
+	PublishedAt *time.Time                    `json:"published_at"` // 上次公布时间
+	Stale       bool                          `json:"stale"`        // 草稿已过期，需重新生成后才能公布
+	Awards      []models.HackathonAward       `json:"awards"`
+	Entries     []models.HackathonResultEntry `json:"entries"`
+}
+
+// ResultDraftEntryInput 主办方对结果草稿条目的调整
+type ResultDraftEntryInput struct {
+	AwardID        uint64 `json:"award_id"` // 0表示不获奖
+	SpecialMention string `json:"special_mention"`
+	Excluded       bool   `json:"excluded"`
+	ExcludeReason  string `json:"exclude_reason"`
+}
+
+// GetPublishedEntries 获取活动已公布的比赛结果，是比赛结果页和活动集锦的唯一数据来源
+func (s *ResultService) GetPublishedEntries(hackathon *models.Hackathon) ([]ResultEntry, error) {
+	var rows []models.HackathonResultEntry
+	if err := database.DB.Where("hackathon_id = ? AND published = ?", hackathon.ID, true).
+		Order("`rank` ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	submissionIDs := make([]uint64, 0, len(rows))
+	for _, row := range rows {
+		submissionIDs = append(submissionIDs, row.SubmissionID)
+	}
+
+	var submissions []models.Submission
+	if len(submissionIDs) > 0 {
+		if err := database.DB.Preload("Team").Preload("Team.Members").Preload("Team.Members.Participant").
+			Where("id IN ?", submissionIDs).Find(&submissions).Error; err != nil {
+			return nil, err
+		}
+	}
+	submissionsByID := make(map[uint64]models.Submission, len(submissions))
+	for _, submission := range submissions {
+		submissionsByID[submission.ID] = submission
+	}
+
+	awards, err := s.getAwards(database.DB, hackathon.ID)
+	if err != nil {
+		return nil, err
+	}
+	awardsByID := make(map[uint64]models.HackathonAward, len(awards))
+	for _, award := range awards {
+		awardsByID[award.ID] = award
+	}
+
+	// 奖项在公布后被修改或删除时使用获奖记录中的快照
+	var winners []models.HackathonWinner
+	if err := database.DB.Where("hackathon_id = ?", hackathon.ID).Find(&winners).Error; err != nil {
+		return nil, err
+	}
+	for _, winner := range winners {
+		if _, ok := awardsByID[winner.AwardID]; !ok {
+			awardsByID[winner.AwardID] = models.HackathonAward{ID: winner.AwardID, HackathonID: winner.HackathonID, Name: winner.AwardName, Prize: winner.Prize}
+		}
+	}
+
+	entries := make([]ResultEntry, 0, len(rows))
+	for _, row := range rows {
+		submission, ok := submissionsByID[row.SubmissionID]
+		if !ok {
+			// 作品已被删除时使用公布时的快照
+			submission = models.Submission{ID: row.SubmissionID, HackathonID: row.HackathonID, TeamID: row.TeamID, Name: row.SubmissionName}
+			submission.Team = models.Team{ID: row.TeamID, Name: row.TeamName}
+		}
+
+		entry := ResultEntry{
+			RankedSubmission: RankedSubmission{
+				Rank:       row.Rank,
+				Submission: submission,
+				Breakdown: RankingBreakdown{
+					JudgeScore:      row.JudgeScore,
+					JudgeCount:      row.JudgeCount,
+					VoteCount:       row.VoteCount,
+					NormalizedVotes: row.NormalizedVotes,
+					JudgeWeight:     row.JudgeWeight,
+					VoteWeight:      100 - row.JudgeWeight,
+					FinalScore:      row.FinalScore,
+					TieBreak:        row.TieBreak,
+				},
+			},
+			SpecialMention: row.SpecialMention,
+		}
+		if row.AwardID != nil {
+			if award, ok := awardsByID[*row.AwardID]; ok {
+				entry.Award = &award
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GetDraft 获取活动的结果草稿
+func (s *ResultService) GetDraft(hackathonID uint64) (*ResultDraft, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+
+	awards, err := s.getAwards(database.DB, hackathonID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.HackathonResultEntry
+	if err := database.DB.Where("hackathon_id = ? AND published = ?", hackathonID, false).
+		Order("engine_rank ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return &ResultDraft{
+		PublishedAt: hackathon.ResultsPublishedAt,
+		Stale:       hackathon.ResultsDraftStale,
+		Awards:      awards,
+		Entries:     entries,
+	}, nil
+}
+
+// GenerateDraft 由结果引擎重新生成结果草稿（投票阶段或结果阶段），主办方此前的调整将被丢弃
+func (s *ResultService) GenerateDraft(hackathonID uint64) (*ResultDraft, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+
+	if hackathon.Status != "voting" && hackathon.Status != "results" {
+		return nil, errors.New("只能在投票阶段或结果阶段生成结果草稿")
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return s.generateDraft(tx, &hackathon, false)
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.GetDraft(hackathonID)
+}
+
+// UpdateDraftEntry 调整结果草稿中的作品：调整奖项、添加特别提名或取消资格
+func (s *ResultService) UpdateDraftEntry(hackathonID, submissionID uint64, input ResultDraftEntryInput) error {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+
+	if hackathon.Status != "voting" && hackathon.Status != "results" {
+		return errors.New("只能在投票阶段或结果阶段调整结果草稿")
+	}
+
+	var entry models.HackathonResultEntry
+	if err := database.DB.Where("hackathon_id = ? AND submission_id = ? AND published = ?", hackathonID, submissionID, false).
+		First(&entry).Error; err != nil {
+		return errors.New("结果草稿中没有该作品")
+	}
+
+	input.SpecialMention = strings.TrimSpace(input.SpecialMention)
+	input.ExcludeReason = strings.TrimSpace(input.ExcludeReason)
+	if utf8.RuneCountInString(input.SpecialMention) > 255 {
+		return errors.New("特别提名不能超过255个字符")
+	}
+	if utf8.RuneCountInString(input.ExcludeReason) > 500 {
+		return errors.New("取消资格原因不能超过500个字符")
+	}
+	if input.Excluded {
+		if input.ExcludeReason == "" {
+			return errors.New("请填写取消资格的原因")
+		}
+		if input.AwardID != 0 {
+			return errors.New("已取消资格的作品不能获奖")
+		}
+	} else {
+		input.ExcludeReason = ""
+	}
+
+	var awardID *uint64
+	if input.AwardID != 0 {
+		var award models.HackathonAward
+		if err := database.DB.Where("id = ? AND hackathon_id = ?", input.AwardID, hackathonID).First(&award).Error; err != nil {
+			return errors.New("奖项不存在")
+		}
+
+		// 检查奖项名额
+		var count int64
+		database.DB.Model(&models.HackathonResultEntry{}).
+			Where("hackathon_id = ? AND published = ? AND award_id = ? AND submission_id <> ?", hackathonID, false, award.ID, submissionID).
+			Count(&count)
+		if quantity := awardQuantity(&award); count >= int64(quantity) {
+			return fmt.Errorf("奖项 %s 的名额已满（共%d名），请先调整其他获奖作品", award.Name, quantity)
+		}
+		awardID = &award.ID
+	}
+
+	overridden := entry.AwardOverridden
+	if (entry.AwardID == nil) != (awardID == nil) || (awardID != nil && *entry.AwardID != *awardID) {
+		overridden = true
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entry).Updates(map[string]interface{}{
+			"award_id":         awardID,
+			"award_overridden": overridden,
+			"special_mention":  input.SpecialMention,
+			"excluded":         input.Excluded,
+			"exclude_reason":   input.ExcludeReason,
+		}).Error; err != nil {
+			return err
+		}
+		return s.renumberDraft(tx, hackathonID)
+	})
+}
+
+// PublishResults 公布结果草稿（仅结果阶段）：生成已公布的结果快照和获奖记录，取消资格的作品同时标记为取消资格
+func (s *ResultService) PublishResults(hackathonID, userID uint64) error {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+
+	if hackathon.Status != "results" {
+		return errors.New("请先将活动切换到结果阶段")
+	}
+	if hackathon.ResultsDraftStale {
+		return errors.New("结果草稿生成后投票数据已变化，请先重新生成结果草稿")
+	}
+
+	var drafts []models.HackathonResultEntry
+	if err := database.DB.Where("hackathon_id = ? AND published = ?", hackathonID, false).
+		Order("engine_rank ASC").Find(&drafts).Error; err != nil {
+		return err
+	}
+	if len(drafts) == 0 {
+		return errors.New("请先生成结果草稿")
+	}
+
+	var excluded []models.HackathonResultEntry
+	now := time.Now()
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if excluded, err = s.publishDraft(tx, hackathonID, drafts, userID, now); err != nil {
+			return err
+		}
+		return tx.Model(&hackathon).Update("results_published_at", now).Error
+	}); err != nil {
+		return err
+	}
+
+	// 通知被取消资格的队伍
+	channelService := &TeamChannelService{}
+	for _, entry := range excluded {
+		channelService.PublishSystemEvent(entry.TeamID, fmt.Sprintf("作品「%s」已被主办方取消比赛资格，原因：%s", entry.SubmissionName, entry.ExcludeReason))
+	}
+
+	// 盲投活动公布结果后向公众订阅者推送各作品得票数
+	(&VoteLeaderboardService{}).Publish(hackathonID)
+	return nil
+}
+
+// PublishLegacyResults 为引入结果草稿前已进入结果阶段的活动补录已公布的比赛结果（服务启动时执行）
+// 这类活动没有任何结果记录，由结果引擎生成结果并直接公布，公布时间记为活动的最后更新时间
+func (s *ResultService) PublishLegacyResults() error {
+	var hackathons []models.Hackathon
+	if err := database.DB.Where("deleted_at IS NULL AND status = ? AND results_published_at IS NULL", "results").
+		Where("NOT EXISTS (?)", database.DB.Model(&models.HackathonResultEntry{}).Select("1").
+			Where("hackathon_result_entries.hackathon_id = hackathons.id")).
+		Find(&hackathons).Error; err != nil {
+		return err
+	}
+
+	for i := range hackathons {
+		hackathon := &hackathons[i]
+		publishedAt := hackathon.UpdatedAt
+		var drafts []models.HackathonResultEntry
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := s.generateDraft(tx, hackathon, false); err != nil {
+				return err
+			}
+			if err := tx.Where("hackathon_id = ? AND published = ?", hackathon.ID, false).
+				Order("engine_rank ASC").Find(&drafts).Error; err != nil {
+				return err
+			}
+			if _, err := s.publishDraft(tx, hackathon.ID, drafts, 0, publishedAt); err != nil {
+				return err
+			}
+			return tx.Model(hackathon).UpdateColumn("results_published_at", publishedAt).Error
+		}); err != nil {
+			return fmt.Errorf("补录活动 %d 的比赛结果失败: %w", hackathon.ID, err)
+		}
+		log.Printf("已补录活动 %d 的比赛结果（%d个作品）", hackathon.ID, len(drafts))
+	}
+	return nil
+}
+
+// publishDraft 将结果草稿复制为已公布的结果快照并重新生成获奖记录，返回被取消资格的条目
+func (s *ResultService) publishDraft(tx *gorm.DB, hackathonID uint64, drafts []models.HackathonResultEntry, userID uint64, now time.Time) ([]models.HackathonResultEntry, error) {
+	awards, err := s.getAwards(tx, hackathonID)
+	if err != nil {
+		return nil, err
+	}
+	awardsByID := make(map[uint64]models.HackathonAward, len(awards))
+	for _, award := range awards {
+		awardsByID[award.ID] = award
+	}
+
+	if err := tx.Where("hackathon_id = ? AND published = ?", hackathonID, true).Delete(&models.HackathonResultEntry{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("hackathon_id = ?", hackathonID).Delete(&models.HackathonWinner{}).Error; err != nil {
+		return nil, err
+	}
+
+	var excluded []models.HackathonResultEntry
+	for _, draft := range drafts {
+		if draft.Excluded {
+			excluded = append(excluded, draft)
+			if err := tx.Model(&models.Submission{}).Where("id = ?", draft.SubmissionID).Updates(map[string]interface{}{
+				"moderation_status": "disqualified",
+				"moderation_reason": draft.ExcludeReason,
+				"moderated_by":      userID,
+				"moderated_at":      now,
+			}).Error; err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		published := draft
+		published.ID = 0
+		published.Published = true
+		if err := tx.Create(&published).Error; err != nil {
+			return nil, fmt.Errorf("保存结果失败: %w", err)
+		}
+
+		if draft.AwardID == nil {
+			continue
+		}
+		award, ok := awardsByID[*draft.AwardID]
+		if !ok {
+			continue
+		}
+		winner := models.HackathonWinner{
+			HackathonID:  hackathonID,
+			SubmissionID: draft.SubmissionID,
+			TeamID:       draft.TeamID,
+			AwardID:      award.ID,
+			AwardName:    award.Name,
+			Prize:        award.Prize,
+			Rank:         draft.Rank,
+			FinalScore:   draft.FinalScore,
+			VoteCount:    draft.VoteCount,
+		}
+		if err := tx.Create(&winner).Error; err != nil {
+			return nil, fmt.Errorf("保存获奖记录失败: %w", err)
+		}
+	}
+	return excluded, nil
+}
+
+// generateDraft 由结果引擎生成结果草稿，按名次和奖项数量分配奖项
+// keepEdits 为 true 时保留主办方在已有草稿中的调整（调整过的奖项、特别提名和取消资格），其余作品按新名次分配剩余名额
+func (s *ResultService) generateDraft(tx *gorm.DB, hackathon *models.Hackathon, keepEdits bool) error {
+	ranked, err := s.RankSubmissions(hackathon)
+	if err != nil {
+		return err
+	}
+
+	awards, err := s.getAwards(tx, hackathon.ID)
+	if err != nil {
+		return err
+	}
+	awardIDs := make(map[uint64]bool, len(awards))
+	for _, award := range awards {
+		awardIDs[award.ID] = true
+	}
+
+	// 保留的调整：取消资格的作品不获奖，调整过奖项的作品保持调整后的奖项（奖项已删除的除外）
+	edits := make(map[uint64]models.HackathonResultEntry)
+	fixed := make(map[uint64]*uint64)
+	if keepEdits {
+		var previous []models.HackathonResultEntry
+		if err := tx.Where("hackathon_id = ? AND published = ?", hackathon.ID, false).Find(&previous).Error; err != nil {
+			return err
+		}
+		for _, entry := range previous {
+			if entry.Excluded {
+				entry.AwardID = nil
+				fixed[entry.SubmissionID] = nil
+			} else if entry.AwardOverridden && (entry.AwardID == nil || awardIDs[*entry.AwardID]) {
+				fixed[entry.SubmissionID] = entry.AwardID
+			} else {
+				entry.AwardOverridden = false
+			}
+			edits[entry.SubmissionID] = entry
+		}
+	}
+	allocation := allocateAwards(ranked, awards, fixed)
+
+	if err := tx.Where("hackathon_id = ? AND published = ?", hackathon.ID, false).Delete(&models.HackathonResultEntry{}).Error; err != nil {
+		return err
+	}
+
+	for _, item := range ranked {
+		entry := models.HackathonResultEntry{
+			HackathonID:     hackathon.ID,
+			SubmissionID:    item.Submission.ID,
+			TeamID:          item.Submission.TeamID,
+			SubmissionName:  item.Submission.Name,
+			TeamName:        item.Submission.Team.Name,
+			EngineRank:      item.Rank,
+			Rank:            item.Rank,
+			JudgeScore:      item.Breakdown.JudgeScore,
+			JudgeCount:      item.Breakdown.JudgeCount,
+			VoteCount:       item.Breakdown.VoteCount,
+			NormalizedVotes: item.Breakdown.NormalizedVotes,
+			JudgeWeight:     item.Breakdown.JudgeWeight,
+			FinalScore:      item.Breakdown.FinalScore,
+			TieBreak:        item.Breakdown.TieBreak,
+		}
+		if awardID, ok := allocation[item.Submission.ID]; ok {
+			entry.AwardID = &awardID
+		}
+		if edit, ok := edits[item.Submission.ID]; ok {
+			entry.AwardOverridden = edit.AwardOverridden
+			entry.SpecialMention = edit.SpecialMention
+			entry.Excluded = edit.Excluded
+			entry.ExcludeReason = edit.ExcludeReason
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("保存结果草稿失败: %w", err)
+		}
+	}
+
+	// 取消资格的作品不占名次
+	if err := s.renumberDraft(tx, hackathon.ID); err != nil {
+		return err
+	}
+	return tx.Model(hackathon).UpdateColumn("results_draft_stale", false).Error
+}
+
+// refreshDraft 投票数据变化后按结果引擎更新已有的结果草稿，保留主办方的调整（没有草稿时不处理）
+func (s *ResultService) refreshDraft(hackathon *models.Hackathon) error {
+	var count int64
+	if err := database.DB.Model(&models.HackathonResultEntry{}).
+		Where("hackathon_id = ? AND published = ?", hackathon.ID, false).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		return s.generateDraft(tx, hackathon, true)
+	})
+}
+
+// renumberDraft 重新计算结果草稿的名次：按结果引擎名次排列，取消资格的作品不占名次
+func (s *ResultService) renumberDraft(tx *gorm.DB, hackathonID uint64) error {
+	var entries []models.HackathonResultEntry
+	if err := tx.Where("hackathon_id = ? AND published = ?", hackathonID, false).
+		Order("engine_rank ASC").Find(&entries).Error; err != nil {
+		return err
+	}
+
+	rank := 0
+	for _, entry := range entries {
+		newRank := 0
+		if !entry.Excluded {
+			rank++
+			newRank = rank
+		}
+		if entry.Rank != newRank {
+			if err := tx.Model(&entry).Update("rank", newRank).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getAwards 获取活动奖项（按奖项排名升序）
+func (s *ResultService) getAwards(db *gorm.DB, hackathonID uint64) ([]models.HackathonAward, error) {
+	var awards []models.HackathonAward
+	if err := db.Where("hackathon_id = ?", hackathonID).Order("`rank` ASC, id ASC").Find(&awards).Error; err != nil {
+		return nil, err
+	}
+	return awards, nil
+}
+
+// awardQuantity 奖项名额（未设置或小于1时按1名计算）
+func awardQuantity(award *models.HackathonAward) int {
+	if award.Quantity < 1 {
+		return 1
+	}
+	return award.Quantity
+}
+
+// allocateAwards 按名次和奖项数量分配奖项：奖项按排名依次分配给排名靠前的 quantity 个作品，返回作品ID到奖项ID的映射
+// fixed 中的作品使用指定的奖项（nil 表示不获奖）并占用该奖项的名额，不参与按名次分配
+func allocateAwards(ranked []RankedSubmission, awards []models.HackathonAward, fixed map[uint64]*uint64) map[uint64]uint64 {
+	allocation := make(map[uint64]uint64)
+	taken := make(map[uint64]int)
+	for submissionID, awardID := range fixed {
+		if awardID != nil {
+			allocation[submissionID] = *awardID
+			taken[*awardID]++
+		}
+	}
+
+	index := 0
+	for _, award := range awards {
+		quantity := awardQuantity(&award) - taken[award.ID]
+		for quantity > 0 && index < len(ranked) {
+			submissionID := ranked[index].Submission.ID
+			index++
+			if _, ok := fixed[submissionID]; ok {
+				continue
+			}
+			allocation[submissionID] = award.ID
+			quantity--
+		}
+	}
+	return allocation
+}
+
+// GetScoringRules 获取活动最终得分计算规则
+func (s *ResultService) GetScoringRules(hackathonID uint64) (*ScoringRules, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+
+	rules := scoringRulesOf(&hackathon)
+	return &rules, nil
+}
+
+// UpdateScoringRules 更新活动最终得分计算规则（结果公布前）
+func (s *ResultService) UpdateScoringRules(hackathonID uint64, rules ScoringRules) error {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+
+	if hackathon.Status == "results" {
+		return errors.New("结果公布后不能修改评分规则")
+	}
+
+	if err := validateScoringRules(rules.JudgeWeight, rules.TieBreakRules); err != nil {
+		return err
+	}
+
+	return database.DB.Model(&hackathon).Updates(map[string]interface{}{
+		"judge_weight":    rules.JudgeWeight,
+		"tie_break_rules": models.StringList(rules.TieBreakRules),
+	}).Error
+}
+
+// PreviewRankings 预览活动当前的排名及得分明细（供主办方在结果公布前查看）
+func (s *ResultService) PreviewRankings(hackathonID uint64) ([]RankedSubmission, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+
+	return s.RankSubmissions(&hackathon)
+}
+
+// SetTieBreakPriority 设置作品的并列名次裁定优先级（数值越小越靠前，0表示取消裁定）
+func (s *ResultService) SetTieBreakPriority(hackathonID, submissionID uint64, priority int) error {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+
+	if hackathon.Status == "results" {
+		return errors.New("结果公布后不能修改并列裁定")
+	}
+	if priority < 0 {
+		return errors.New("裁定优先级不能小于0")
+	}
+
+	var submission models.Submission
+	if err := database.DB.Where("id = ? AND hackathon_id = ?", submissionID, hackathonID).First(&submission).Error; err != nil {
+		return errors.New("作品不存在")
+	}
+
+	return database.DB.Model(&submission).Update("tie_break_priority", priority).Error
+}
+
+// RankSubmissions 计算活动中参与排名作品的最终得分并排名
+// 最终得分 = 评委平均分 × 评委权重 + 归一化投票得分 × 投票权重；得分相同时依次按并列规则决定先后，仍相同时作品ID小者优先
+func (s *ResultService) RankSubmissions(hackathon *models.Hackathon) ([]RankedSubmission, error) {
+	// 获取所有参与排名的作品（已隐藏或取消资格的作品除外）
+	var submissions []models.Submission
+	if err := database.DB.Preload("Team").Preload("Team.Members").Preload("Team.Members.Participant").
+		Where("hackathon_id = ? AND draft = 0 AND moderation_status IN ?", hackathon.ID, PublicModerationStatuses).
+		Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+
+	submissionIDs := make([]uint64, 0, len(submissions))
+	for _, submission := range submissions {
+		submissionIDs = append(submissionIDs, submission.ID)
+	}
+
+	voteCounts, normalizedVotes, err := s.voteScores(hackathon, submissionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	judgingService := &JudgingService{}
+	summaries, err := judgingService.GetScoreSummary(hackathon.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	judgeSummaries := make(map[uint64]SubmissionJudgeSummary, len(summaries))
+	for _, summary := range summaries {
+		judgeSummaries[summary.SubmissionID] = summary
+	}
+
+	rules := scoringRulesOf(hackathon)
+
+	ranked := make([]RankedSubmission, 0, len(submissions))
+	for _, submission := range submissions {
+		summary := judgeSummaries[submission.ID]
+		breakdown := RankingBreakdown{
+			JudgeScore:      summary.MeanScore,
+			JudgeCount:      summary.JudgeCount,
+			VoteCount:       voteCounts[submission.ID],
+			NormalizedVotes: normalizedVotes[submission.ID],
+			JudgeWeight:     rules.JudgeWeight,
+			VoteWeight:      rules.VoteWeight,
+		}
+		breakdown.FinalScore = roundScore((breakdown.JudgeScore*float64(rules.JudgeWeight) +
+			breakdown.NormalizedVotes*float64(rules.VoteWeight)) / 100)
+		ranked = append(ranked, RankedSubmission{Submission: submission, Breakdown: breakdown})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Breakdown.FinalScore != ranked[j].Breakdown.FinalScore {
+			return ranked[i].Breakdown.FinalScore > ranked[j].Breakdown.FinalScore
+		}
+		if rule := decidingTieBreakRule(&ranked[i], &ranked[j], rules.TieBreakRules); rule != "" {
+			return compareByTieBreakRule(&ranked[i], &ranked[j], rule) < 0
+		}
+		return ranked[i].Submission.ID < ranked[j].Submission.ID
+	})
+
+	// 记录名次以及与前一名并列时决定先后的规则
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+		if i == 0 || ranked[i].Breakdown.FinalScore != ranked[i-1].Breakdown.FinalScore {
+			continue
+		}
+		if rule := decidingTieBreakRule(&ranked[i-1], &ranked[i], rules.TieBreakRules); rule != "" {
+			ranked[i].Breakdown.TieBreak = rule
+		} else {
+			ranked[i].Breakdown.TieBreak = "submission_id"
+		}
+	}
+
+	return ranked, nil
+}
+
+// voteScores 计算作品的得票数和归一化投票得分（0-100）
+// 普通投票、平方投票和波达计数按最高得票数归一化；即时决选按名次归一化
+func (s *ResultService) voteScores(hackathon *models.Hackathon, submissionIDs []uint64) (map[uint64]int64, map[uint64]float64, error) {
+	counts := make(map[uint64]int64, len(submissionIDs))
+	normalized := make(map[uint64]float64, len(submissionIDs))
+	if len(submissionIDs) == 0 {
+		return counts, normalized, nil
+	}
+
+	if hackathon.VotingMode == "ranked" {
+		ballotService := &RankedBallotService{}
+		tallies, err := ballotService.Tally(hackathon, submissionIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, tally := range tallies {
+			counts[tally.SubmissionID] = tally.Score
+			if hackathon.RankedTallyMethod != "irv" {
+				continue
+			}
+			// 同一轮次票数相同的作品得分相同
+			if i > 0 && tally.Round == tallies[i-1].Round && tally.Score == tallies[i-1].Score {
+				normalized[tally.SubmissionID] = normalized[tallies[i-1].SubmissionID]
+			} else {
+				normalized[tally.SubmissionID] = roundScore(float64(len(tallies)-i) / float64(len(tallies)) * 100)
+			}
+		}
+		if hackathon.RankedTallyMethod == "irv" {
+			return counts, normalized, nil
+		}
+	} else {
+		var rows []struct {
+			SubmissionID uint64
+			Total        int64
+		}
+		if err := database.DB.Model(&models.Vote{}).Select("submission_id, SUM(votes) AS total").
+			Where("submission_id IN ? AND voided = ?", submissionIDs, false).Group("submission_id").Scan(&rows).Error; err != nil {
+			return nil, nil, err
+		}
+		for _, row := range rows {
+			counts[row.SubmissionID] = row.Total
+		}
+	}
+
+	var maxCount int64
+	for _, count := range counts {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+	if maxCount > 0 {
+		for id, count := range counts {
+			normalized[id] = roundScore(float64(count) / float64(maxCount) * 100)
+		}
+	}
+
+	return counts, normalized, nil
+}
+
+// decidingTieBreakRule 返回第一条能区分两个作品先后的并列规则，都无法区分时返回空字符串
+func decidingTieBreakRule(a, b *RankedSubmission, rules []string) string {
+	for _, rule := range rules {
+		if compareByTieBreakRule(a, b, rule) != 0 {
+			return rule
+		}
+	}
+	return ""
+}
+
+// compareByTieBreakRule 按并列规则比较两个作品，a 应排在前面时返回负数
+func compareByTieBreakRule(a, b *RankedSubmission, rule string) int {
+	switch rule {
+	case "judge_score":
+		return compareFloat(b.Breakdown.JudgeScore, a.Breakdown.JudgeScore)
+	case "vote_count":
+		return compareFloat(float64(b.Breakdown.VoteCount), float64(a.Breakdown.VoteCount))
+	case "earliest_finalized":
+		return compareTime(a.Submission.FinalizedAt, b.Submission.FinalizedAt)
+	case "organizer_decision":
+		// 已裁定的作品排在未裁定的作品之前
+		pa, pb := a.Submission.TieBreakPriority, b.Submission.TieBreakPriority
+		if pa == pb {
+			return 0
+		}
+		if pa == 0 {
+			return 1
+		}
+		if pb == 0 {
+			return -1
+		}
+		return pa - pb
+	}
+	return 0
+}
+
+func compareFloat(a, b float64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// compareTime 比较时间先后，未设置的时间排在最后
+func compareTime(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	case a.Before(*b):
+		return -1
+	case b.Before(*a):
+		return 1
+	}
+	return 0
+}
+
+// scoringRulesOf 提取活动的最终得分计算规则（未设置并列规则时使用默认顺序）
+func scoringRulesOf(hackathon *models.Hackathon) ScoringRules {
+	rules := ScoringRules{
+		JudgeWeight:   hackathon.JudgeWeight,
+		VoteWeight:    100 - hackathon.JudgeWeight,
+		TieBreakRules: []string(hackathon.TieBreakRules),
+	}
+	if len(rules.TieBreakRules) == 0 {
+		rules.TieBreakRules = defaultTieBreakRules
+	}
+	return rules
+}
+
+// validateScoringRules 校验最终得分计算规则
+func validateScoringRules(judgeWeight int, tieBreakRules []string) error {
+	if judgeWeight < 0 || judgeWeight > 100 {
+		return errors.New("评委评分权重必须在0到100之间")
+	}
+
+	seen := make(map[string]bool)
+	for _, rule := range tieBreakRules {
+		if !containsString(defaultTieBreakRules, rule) {
+			return fmt.Errorf("无效的并列规则: %s（可选: %s）", rule, strings.Join(defaultTieBreakRules, ", "))
+		}
+		if seen[rule] {
+			return fmt.Errorf("并列规则 %s 重复", rule)
+		}
+		seen[rule] = true
+	}
+	return nil
+}
+
+// containsString 判断字符串列表中是否包含指定值
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+
+	"hackathon-backend/models"
+)
+
+func rankedSubmissions(ids ...uint64) []RankedSubmission {
+	ranked := make([]RankedSubmission, 0, len(ids))
+	for i, id := range ids {
+		ranked = append(ranked, RankedSubmission{Rank: i + 1, Submission: models.Submission{ID: id}})
+	}
+	return ranked
+}
+
+func TestAllocateAwards(t *testing.T) {
+	awards := []models.HackathonAward{
+		{ID: 1, Name: "一等奖", Quantity: 1, Rank: 1},
+		{ID: 2, Name: "二等奖", Quantity: 2, Rank: 2},
+	}
+
+	allocation := allocateAwards(rankedSubmissions(11, 12, 13, 14), awards, nil)
+	want := map[uint64]uint64{11: 1, 12: 2, 13: 2}
+	if len(allocation) != len(want) {
+		t.Fatalf("分配结果为 %v，期望 %v", allocation, want)
+	}
+	for id, awardID := range want {
+		if allocation[id] != awardID {
+			t.Fatalf("分配结果为 %v，期望 %v", allocation, want)
+		}
+	}
+}
+
+func TestAllocateAwardsKeepsFixedEntries(t *testing.T) {
+	awards := []models.HackathonAward{
+		{ID: 1, Name: "一等奖", Quantity: 1, Rank: 1},
+		{ID: 2, Name: "二等奖", Quantity: 2, Rank: 2},
+	}
+	second := uint64(2)
+
+	// 作品11被取消资格，作品15被主办方调整为二等奖，占用一个二等奖名额
+	fixed := map[uint64]*uint64{11: nil, 15: &second}
+	allocation := allocateAwards(rankedSubmissions(11, 12, 13, 14, 15), awards, fixed)
+	want := map[uint64]uint64{12: 1, 13: 2, 15: 2}
+	if len(allocation) != len(want) {
+		t.Fatalf("分配结果为 %v，期望 %v", allocation, want)
+	}
+	for id, awardID := range want {
+		if allocation[id] != awardID {
+			t.Fatalf("分配结果为 %v，期望 %v", allocation, want)
+		}
+	}
+}
+
+func TestAllocateAwardsZeroQuantity(t *testing.T) {
+	awards := []models.HackathonAward{{ID: 1, Name: "最佳创意奖", Quantity: 0, Rank: 1}}
+	if quantity := awardQuantity(&awards[0]); quantity != 1 {
+		t.Fatalf("名额为 %d，期望 1", quantity)
+	}
+
+	allocation := allocateAwards(rankedSubmissions(11, 12), awards, nil)
+	if len(allocation) != 1 || allocation[11] != 1 {
+		t.Fatalf("分配结果为 %v，期望只有作品11获奖", allocation)
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+	"hackathon-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// similarityReportThreshold 综合相似度达到该值时生成报告
+	similarityReportThreshold = 0.5
+	// similarityLinkMatchScore 存在相同链接时的最低综合相似度
+	similarityLinkMatchScore = 0.9
+)
+
+type SimilarityService struct{}
+
+// similarityCandidate 参与比较的作品
+type similarityCandidate struct {
+	submission    models.Submission
+	hackathonName string
+	links         map[string]bool
+}
+
+// AnalyzeHackathon 分析活动作品之间、以及与往届活动作品之间的相似度，生成报告
+// 重新分析时保留主办方已处理的报告状态，返回报告数量
+func (s *SimilarityService) AnalyzeHackathon(hackathonID uint64) (int, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return 0, errors.New("活动不存在")
+	}
+
+	var submissions []models.Submission
+	if err := database.DB.Where("hackathon_id = ?", hackathonID).Order("id ASC").Find(&submissions).Error; err != nil {
+		return 0, err
+	}
+	current := make([]similarityCandidate, 0, len(submissions))
+	for _, submission := range submissions {
+		current = append(current, newSimilarityCandidate(submission, hackathon.Name))
+	}
+
+	// 往届活动：已公布结果的活动（包括已归档的活动）中已定稿的作品
+	var archivedHackathons []models.Hackathon
+	if err := database.DB.Unscoped().Where("id <> ? AND status = ?", hackathonID, "results").Find(&archivedHackathons).Error; err != nil {
+		return 0, err
+	}
+	archived := make([]similarityCandidate, 0)
+	if len(archivedHackathons) > 0 {
+		names := make(map[uint64]string)
+		ids := make([]uint64, 0, len(archivedHackathons))
+		for _, h := range archivedHackathons {
+			names[h.ID] = h.Name
+			ids = append(ids, h.ID)
+		}
+		var archivedSubmissions []models.Submission
+		if err := database.DB.Where("hackathon_id IN ? AND draft = 0", ids).Find(&archivedSubmissions).Error; err != nil {
+			return 0, err
+		}
+		for _, submission := range archivedSubmissions {
+			archived = append(archived, newSimilarityCandidate(submission, names[submission.HackathonID]))
+		}
+	}
+
+	reports := make([]models.SubmissionSimilarityReport, 0)
+	for i := range current {
+		for j := i + 1; j < len(current); j++ {
+			if report := compareSubmissions(&current[i], &current[j], "hackathon"); report != nil {
+				reports = append(reports, *report)
+			}
+		}
+		for j := range archived {
+			if report := compareSubmissions(&current[i], &archived[j], "archive"); report != nil {
+				reports = append(reports, *report)
+			}
+		}
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []models.SubmissionSimilarityReport
+		if err := tx.Where("hackathon_id = ?", hackathonID).Find(&existing).Error; err != nil {
+			return err
+		}
+		reviewed := make(map[[2]uint64]models.SubmissionSimilarityReport)
+		for _, report := range existing {
+			if report.Status != "pending" {
+				reviewed[[2]uint64{report.SubmissionID, report.MatchedSubmissionID}] = report
+			}
+		}
+
+		if err := tx.Where("hackathon_id = ?", hackathonID).Delete(&models.SubmissionSimilarityReport{}).Error; err != nil {
+			return err
+		}
+
+		for i := range reports {
+			reports[i].HackathonID = hackathonID
+			if old, ok := reviewed[[2]uint64{reports[i].SubmissionID, reports[i].MatchedSubmissionID}]; ok {
+				reports[i].Status = old.Status
+				reports[i].ReviewedBy = old.ReviewedBy
+			}
+			if err := tx.Create(&reports[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(reports), nil
+}
+
+// GetReports 获取活动的相似度报告（按相似度降序）
+func (s *SimilarityService) GetReports(hackathonID uint64, scope, status string) ([]models.SubmissionSimilarityReport, error) {
+	query := database.DB.Where("hackathon_id = ?", hackathonID)
+	if scope != "" {
+		query = query.Where("scope = ?", scope)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var reports []models.SubmissionSimilarityReport
+	if err := query.Order("score DESC").Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// ReviewReport 主办方处理相似度报告（confirmed-确认疑似抄袭，dismissed-忽略）
+func (s *SimilarityService) ReviewReport(hackathonID, reportID, userID uint64, status string) error {
+	if status != "confirmed" && status != "dismissed" && status != "pending" {
+		return errors.New("无效的报告状态")
+	}
+
+	var report models.SubmissionSimilarityReport
+	if err := database.DB.Where("id = ? AND hackathon_id = ?", reportID, hackathonID).First(&report).Error; err != nil {
+		return errors.New("报告不存在")
+	}
+
+	updates := map[string]interface{}{"status": status, "reviewed_by": &userID}
+	if status == "pending" {
+		updates["reviewed_by"] = nil
+	}
+	return database.DB.Model(&report).Updates(updates).Error
+}
+
+func newSimilarityCandidate(submission models.Submission, hackathonName string) similarityCandidate {
+	links := make(map[string]bool)
+	for _, link := range []string{submission.Link, submission.RepoURL, submission.DemoURL, submission.VideoURL} {
+		if normalized := utils.NormalizeURL(link); normalized != "" {
+			links[normalized] = true
+		}
+	}
+	return similarityCandidate{submission: submission, hackathonName: hackathonName, links: links}
+}
+
+// compareSubmissions 比较两个作品，相似度达到阈值时返回报告
+func compareSubmissions(a, b *similarityCandidate, scope string) *models.SubmissionSimilarityReport {
+	nameScore := utils.TextSimilarity(a.submission.Name, b.submission.Name)
+	descriptionScore := utils.TextSimilarity(a.submission.Description, b.submission.Description)
+
+	matchedLinks := make([]string, 0)
+	for link := range a.links {
+		if b.links[link] {
+			matchedLinks = append(matchedLinks, link)
+		}
+	}
+	sort.Strings(matchedLinks)
+
+	score := 0.3*nameScore + 0.7*descriptionScore
+	if len(matchedLinks) > 0 {
+		score = math.Max(score, similarityLinkMatchScore)
+	}
+	if score < similarityReportThreshold {
+		return nil
+	}
+
+	links := strings.Join(matchedLinks, ",")
+	if len(links) > 1000 {
+		links = links[:1000]
+	}
+
+	return &models.SubmissionSimilarityReport{
+		SubmissionID:          a.submission.ID,
+		SubmissionName:        a.submission.Name,
+		MatchedSubmissionID:   b.submission.ID,
+		MatchedSubmissionName: b.submission.Name,
+		MatchedHackathonID:    b.submission.HackathonID,
+		MatchedHackathonName:  b.hackathonName,
+		Scope:                 scope,
+		Score:                 math.Round(score*1000) / 1000,
+		NameScore:             math.Round(nameScore*1000) / 1000,
+		DescriptionScore:      math.Round(descriptionScore*1000) / 1000,
+		MatchedLinks:          links,
+		Status:                "pending",
+	}
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+	"hackathon-backend/storage"
+	"hackathon-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// defaultAttachmentTypes 活动未配置时允许上传的附件扩展名
+var defaultAttachmentTypes = []string{
+	"pdf", "ppt", "pptx", "key", "doc", "docx", "md", "txt",
+	"zip", "rar", "7z", "gz", "tgz",
+	"apk", "ipa", "dmg", "exe",
+	"mp4", "mov", "webm",
+	"png", "jpg", "jpeg", "gif",
+}
+
+type SubmissionAttachmentService struct{}
+
+// UploadAttachment 上传作品附件（队伍成员，提交阶段内）
+func (s *SubmissionAttachmentService) UploadAttachment(submissionID, participantID uint64, file *multipart.FileHeader) (*models.SubmissionAttachment, error) {
+	var submission models.Submission
+	if err := database.DB.Where("id = ?", submissionID).First(&submission).Error; err != nil {
+		return nil, errors.New("作品不存在")
+	}
+
+	teamService := &TeamService{}
+	if !teamService.IsTeamMember(submission.TeamID, participantID) {
+		return nil, errors.New("您没有权限修改此作品")
+	}
+
+	if err := checkSubmissionEditable(&submission); err != nil {
+		return nil, err
+	}
+
+	hackathon, err := checkSubmissionWindow(submission.HackathonID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 校验文件大小
+	maxSize := int64(hackathon.MaxAttachmentSizeMB) << 20
+	if file.Size <= 0 {
+		return nil, errors.New("文件不能为空")
+	}
+	if maxSize > 0 && file.Size > maxSize {
+		return nil, fmt.Errorf("文件大小不能超过%dMB", hackathon.MaxAttachmentSizeMB)
+	}
+
+	// 校验文件类型
+	fileName := filepath.Base(file.Filename)
+	ext := attachmentExt(fileName)
+	if !s.isAllowedType(hackathon, ext) {
+		return nil, errors.New("不支持的文件类型: " + ext)
+	}
+
+	// 校验附件数量
+	var count int64
+	database.DB.Model(&models.SubmissionAttachment{}).Where("submission_id = ?", submissionID).Count(&count)
+	if hackathon.MaxAttachments > 0 && int(count) >= hackathon.MaxAttachments {
+		return nil, fmt.Errorf("每个作品最多上传%d个附件", hackathon.MaxAttachments)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, errors.New("读取文件失败")
+	}
+	defer src.Close()
+
+	// 根据文件内容识别类型，不信任客户端提供的 Content-Type
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, errors.New("读取文件失败")
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+
+	key, err := attachmentKey(submissionID, ext)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if err := storage.Default.Put(ctx, key, io.MultiReader(bytes.NewReader(head), src), file.Size, contentType); err != nil {
+		return nil, errors.New("保存文件失败: " + err.Error())
+	}
+
+	attachment := models.SubmissionAttachment{
+		SubmissionID: submissionID,
+		UploaderID:   participantID,
+		FileName:     fileName,
+		StorageKey:   key,
+		ContentType:  contentType,
+		Size:         file.Size,
+	}
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveSubmissionHistory(tx, &submission, participantID); err != nil {
+			return err
+		}
+		return tx.Create(&attachment).Error
+	}); err != nil {
+		storage.Default.Delete(ctx, key)
+		return nil, errors.New("保存附件失败: " + err.Error())
+	}
+
+	// 推送队伍频道系统事件
+	channelService := &TeamChannelService{}
+	channelService.PublishSystemEvent(submission.TeamID, participantDisplayName(participantID)+" 上传了附件 "+fileName)
+
+	attachment.DownloadURL = s.DownloadURL(attachment.ID)
+	return &attachment, nil
+}
+
+// ListAttachments 获取作品附件列表（草稿、已隐藏和已取消资格的作品仅队伍成员可查看）
+func (s *SubmissionAttachmentService) ListAttachments(submissionID, participantID uint64) ([]models.SubmissionAttachment, error) {
+	if _, _, err := loadVisibleSubmission(submissionID, participantID); err != nil {
+		return nil, err
+	}
+
+	var attachments []models.SubmissionAttachment
+	if err := database.DB.Where("submission_id = ?", submissionID).
+		Order("created_at ASC").
+		Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	s.FillDownloadURLs(attachments)
+	return attachments, nil
+}
+
+// DeleteAttachment 删除作品附件（队伍成员，提交阶段内）
+// 附件记录软删除，文件保留以便通过修改记录下载
+func (s *SubmissionAttachmentService) DeleteAttachment(submissionID, attachmentID, participantID uint64) error {
+	var submission models.Submission
+	if err := database.DB.Where("id = ?", submissionID).First(&submission).Error; err != nil {
+		return errors.New("作品不存在")
+	}
+
+	teamService := &TeamService{}
+	if !teamService.IsTeamMember(submission.TeamID, participantID) {
+		return errors.New("您没有权限修改此作品")
+	}
+
+	if err := checkSubmissionEditable(&submission); err != nil {
+		return err
+	}
+
+	if _, err := checkSubmissionWindow(submission.HackathonID); err != nil {
+		return err
+	}
+
+	var attachment models.SubmissionAttachment
+	if err := database.DB.Where("id = ? AND submission_id = ?", attachmentID, submissionID).First(&attachment).Error; err != nil {
+		return errors.New("附件不存在")
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveSubmissionHistory(tx, &submission, participantID); err != nil {
+			return err
+		}
+		return tx.Delete(&attachment).Error
+	}); err != nil {
+		return err
+	}
+
+	// 推送队伍频道系统事件
+	channelService := &TeamChannelService{}
+	channelService.PublishSystemEvent(submission.TeamID, participantDisplayName(participantID)+" 删除了附件 "+attachment.FileName)
+
+	return nil
+}
+
+// OpenAttachment 校验签名下载链接并打开附件，调用方负责关闭返回的 Reader
+// 已删除的附件仍可通过修改记录中的链接下载
+func (s *SubmissionAttachmentService) OpenAttachment(attachmentID uint64, expires int64, signature string) (*models.SubmissionAttachment, io.ReadCloser, error) {
+	if !utils.VerifyResourceSignature("attachment", attachmentID, expires, signature) {
+		return nil, nil, errors.New("下载链接无效或已过期")
+	}
+
+	var attachment models.SubmissionAttachment
+	if err := database.DB.Unscoped().Where("id = ?", attachmentID).First(&attachment).Error; err != nil {
+		return nil, nil, errors.New("附件不存在")
+	}
+
+	reader, err := storage.Default.Get(context.Background(), attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, errors.New("附件文件不存在")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &attachment, reader, nil
+}
+
+// DownloadURL 生成附件的签名下载链接
+func (s *SubmissionAttachmentService) DownloadURL(attachmentID uint64) string {
+	expires, signature := utils.SignResource("attachment", attachmentID, time.Duration(config.AppConfig.DownloadURLExpireMin)*time.Minute)
+	return fmt.Sprintf("/api/v1/arena/attachments/%d/download?expires=%d&signature=%s", attachmentID, expires, signature)
+}
+
+// FillDownloadURLs 为附件列表填充签名下载链接
+func (s *SubmissionAttachmentService) FillDownloadURLs(attachments []models.SubmissionAttachment) {
+	for i := range attachments {
+		attachments[i].DownloadURL = s.DownloadURL(attachments[i].ID)
+	}
+}
+
+// isAllowedType 检查扩展名是否在活动允许的附件类型中
+func (s *SubmissionAttachmentService) isAllowedType(hackathon *models.Hackathon, ext string) bool {
+	if ext == "" {
+		return false
+	}
+	allowed := defaultAttachmentTypes
+	if strings.TrimSpace(hackathon.AllowedAttachmentTypes) != "" {
+		allowed = strings.Split(hackathon.AllowedAttachmentTypes, ",")
+	}
+	for _, t := range allowed {
+		if strings.TrimPrefix(strings.ToLower(strings.TrimSpace(t)), ".") == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// attachmentExt 获取附件扩展名（小写，不含点）
+func attachmentExt(fileName string) string {
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+}
+
+// attachmentKey 生成附件的存储对象键
+func attachmentKey(submissionID uint64, ext string) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.New("生成文件名失败")
+	}
+	return fmt.Sprintf("submissions/%d/%d_%s.%s", submissionID, time.Now().UnixNano(), hex.EncodeToString(buf), ext), nil
+}
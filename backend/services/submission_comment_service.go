@@ -0,0 +1,182 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// maxCommentLength 评论内容的最大长度（字符数）
+const maxCommentLength = 2000
+
+type SubmissionCommentService struct{}
+
+// CreateComment 发表评论或回复（作品所属队伍成员的评论标记为官方回复）
+// 回复评论的回复时挂到同一个顶层评论下
+func (s *SubmissionCommentService) CreateComment(submissionID, participantID uint64, parentID *uint64, content string) (*models.SubmissionComment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("评论内容不能为空")
+	}
+	if utf8.RuneCountInString(content) > maxCommentLength {
+		return nil, fmt.Errorf("评论内容不能超过%d个字符", maxCommentLength)
+	}
+
+	submission, isMember, err := loadVisibleSubmission(submissionID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := models.SubmissionComment{
+		HackathonID:   submission.HackathonID,
+		SubmissionID:  submission.ID,
+		ParticipantID: participantID,
+		Content:       content,
+		Official:      isMember,
+	}
+
+	if parentID != nil {
+		var parent models.SubmissionComment
+		if err := database.DB.Where("id = ? AND submission_id = ?", *parentID, submissionID).First(&parent).Error; err != nil {
+			return nil, errors.New("回复的评论不存在")
+		}
+		rootID := parent.ID
+		if parent.ParentID != nil {
+			rootID = *parent.ParentID
+		}
+		comment.ParentID = &rootID
+	}
+
+	if err := database.DB.Create(&comment).Error; err != nil {
+		return nil, err
+	}
+
+	// 推送队伍频道系统事件（队伍成员自己的回复不提醒）
+	if !isMember {
+		channelService := &TeamChannelService{}
+		channelService.PublishSystemEvent(submission.TeamID, participantDisplayName(participantID)+" 评论了作品："+truncateRunes(content, 50))
+	}
+
+	database.DB.Preload("Participant").Where("id = ?", comment.ID).First(&comment)
+	return &comment, nil
+}
+
+// GetComments 获取作品评论（顶层评论按时间倒序分页，回复按时间正序）
+func (s *SubmissionCommentService) GetComments(submissionID, participantID uint64, page, pageSize int) ([]models.SubmissionComment, int64, error) {
+	if _, _, err := loadVisibleSubmission(submissionID, participantID); err != nil {
+		return nil, 0, err
+	}
+
+	var comments []models.SubmissionComment
+	var total int64
+
+	query := database.DB.Model(&models.SubmissionComment{}).Where("submission_id = ? AND parent_id IS NULL", submissionID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Participant").
+		Preload("Replies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Replies.Participant").
+		Order("created_at DESC").
+		Offset(offset).Limit(pageSize).Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// DeleteComment 删除自己的评论（删除顶层评论时其回复一并删除）
+func (s *SubmissionCommentService) DeleteComment(commentID, participantID uint64) error {
+	var comment models.SubmissionComment
+	if err := database.DB.Where("id = ?", commentID).First(&comment).Error; err != nil {
+		return errors.New("评论不存在")
+	}
+
+	if comment.ParticipantID != participantID {
+		return errors.New("只能删除自己的评论")
+	}
+
+	return database.DB.Where("id = ? OR parent_id = ?", comment.ID, comment.ID).Delete(&models.SubmissionComment{}).Error
+}
+
+// GetHackathonComments 获取活动的全部评论（主办方审核，按时间倒序）
+func (s *SubmissionCommentService) GetHackathonComments(hackathonID uint64, page, pageSize int, keyword string) ([]models.SubmissionComment, int64, error) {
+	var comments []models.SubmissionComment
+	var total int64
+
+	query := database.DB.Model(&models.SubmissionComment{}).Where("hackathon_id = ?", hackathonID)
+	if keyword != "" {
+		query = query.Where("content LIKE ?", "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Participant").
+		Order("created_at DESC").
+		Offset(offset).Limit(pageSize).Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// RemoveComment 主办方删除评论（删除顶层评论时其回复一并删除）
+func (s *SubmissionCommentService) RemoveComment(hackathonID, commentID, userID uint64) error {
+	var comment models.SubmissionComment
+	if err := database.DB.Where("id = ? AND hackathon_id = ?", commentID, hackathonID).First(&comment).Error; err != nil {
+		return errors.New("评论不存在")
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.SubmissionComment{}).
+			Where("id = ? OR parent_id = ?", comment.ID, comment.ID).
+			Update("deleted_by", userID).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ? OR parent_id = ?", comment.ID, comment.ID).Delete(&models.SubmissionComment{}).Error
+	})
+}
+
+// CountComments 统计作品的评论数
+func (s *SubmissionCommentService) CountComments(submissionIDs []uint64) map[uint64]int64 {
+	counts := make(map[uint64]int64)
+	if len(submissionIDs) == 0 {
+		return counts
+	}
+
+	var rows []struct {
+		SubmissionID uint64
+		Count        int64
+	}
+	database.DB.Model(&models.SubmissionComment{}).
+		Select("submission_id, COUNT(*) AS count").
+		Where("submission_id IN ?", submissionIDs).
+		Group("submission_id").
+		Scan(&rows)
+	for _, row := range rows {
+		counts[row.SubmissionID] = row.Count
+	}
+	return counts
+}
+
+// truncateRunes 按字符数截断文本
+func truncateRunes(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n]) + "..."
+}
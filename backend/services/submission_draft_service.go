@@ -0,0 +1,220 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+)
+
+// draftReminderWindow 距离提交截止多久时提醒仍未定稿的作品
+const draftReminderWindow = 2 * time.Hour
+
+type SubmissionDraftService struct{}
+
+// SubmissionChecklistItem 定稿检查项
+type SubmissionChecklistItem struct {
+	Key      string `json:"key"`
+	Label    string `json:"label"`
+	Required bool   `json:"required"`
+	Done     bool   `json:"done"`
+}
+
+// Checklist 生成作品的定稿检查清单（必填项全部完成才能定稿）
+// 代码仓库地址是否必填由活动设置决定
+func (s *SubmissionDraftService) Checklist(submission *models.Submission) []SubmissionChecklistItem {
+	var attachmentCount int64
+	database.DB.Model(&models.SubmissionAttachment{}).Where("submission_id = ?", submission.ID).Count(&attachmentCount)
+
+	var hackathon models.Hackathon
+	database.DB.Select("id", "require_repo_url").Where("id = ?", submission.HackathonID).First(&hackathon)
+
+	checklist := []SubmissionChecklistItem{
+		{Key: "name", Label: "作品名称", Required: true, Done: submission.Name != ""},
+		{Key: "description", Label: "作品描述", Required: true, Done: submission.Description != ""},
+		{Key: "link", Label: "作品链接", Required: true, Done: submission.Link != ""},
+		{Key: "repo_url", Label: "代码仓库地址", Required: hackathon.RequireRepoURL, Done: submission.RepoURL != ""},
+		{Key: "demo", Label: "演示地址或演示视频", Required: false, Done: submission.DemoURL != "" || submission.VideoURL != ""},
+		{Key: "tech_stack", Label: "技术栈", Required: false, Done: submission.TechStack != ""},
+		{Key: "cover_image", Label: "封面图片", Required: false, Done: submission.CoverImage != ""},
+		{Key: "attachments", Label: "附件", Required: false, Done: attachmentCount > 0},
+	}
+
+	// 活动自定义字段
+	fieldService := &SubmissionFieldService{}
+	fields, _ := fieldService.GetFields(submission.HackathonID)
+	for _, field := range fields {
+		checklist = append(checklist, SubmissionChecklistItem{
+			Key:      "custom_fields." + field.Key,
+			Label:    field.Label,
+			Required: field.Required,
+			Done:     submission.CustomFields[field.Key] != "",
+		})
+	}
+	return checklist
+}
+
+// GetMySubmission 获取参赛者所在队伍的作品（含草稿）及定稿检查清单
+func (s *SubmissionDraftService) GetMySubmission(hackathonID, participantID uint64) (map[string]interface{}, error) {
+	teamService := &TeamService{}
+	team, err := teamService.GetUserTeam(hackathonID, participantID)
+	if err != nil || team == nil {
+		return nil, errors.New("您还没有加入队伍")
+	}
+
+	var submission models.Submission
+	if err := database.DB.Where("hackathon_id = ? AND team_id = ?", hackathonID, team.ID).First(&submission).Error; err != nil {
+		return nil, nil // 尚未提交作品，返回 nil 而不是错误
+	}
+
+	submissionService := &SubmissionService{}
+	detail, err := submissionService.GetSubmissionByID(submission.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"submission": detail,
+		"checklist":  s.Checklist(detail),
+	}, nil
+}
+
+// Finalize 作品定稿（仅队长，提交阶段内），定稿后作品公开并参与投票
+func (s *SubmissionDraftService) Finalize(submissionID, leaderID uint64) ([]SubmissionChecklistItem, error) {
+	submission, err := s.loadForLeader(submissionID, leaderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission.Draft == 0 {
+		return nil, errors.New("作品已定稿")
+	}
+
+	checklist := s.Checklist(submission)
+	for _, item := range checklist {
+		if item.Required && !item.Done {
+			return checklist, fmt.Errorf("请先完善%s", item.Label)
+		}
+	}
+
+	now := time.Now()
+	if err := database.DB.Model(submission).Updates(map[string]interface{}{
+		"draft":        0,
+		"finalized_at": &now,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	// 推送队伍频道系统事件
+	channelService := &TeamChannelService{}
+	channelService.PublishSystemEvent(submission.TeamID, "作品已定稿")
+
+	return checklist, nil
+}
+
+// Unfinalize 取消定稿（仅队长，提交截止前），作品回到草稿状态
+func (s *SubmissionDraftService) Unfinalize(submissionID, leaderID uint64) error {
+	submission, err := s.loadForLeader(submissionID, leaderID)
+	if err != nil {
+		return err
+	}
+
+	if submission.Draft == 1 {
+		return errors.New("作品尚未定稿")
+	}
+
+	if err := database.DB.Model(submission).Updates(map[string]interface{}{
+		"draft":        1,
+		"finalized_at": nil,
+	}).Error; err != nil {
+		return err
+	}
+
+	// 推送队伍频道系统事件
+	channelService := &TeamChannelService{}
+	channelService.PublishSystemEvent(submission.TeamID, "作品已取消定稿，请在截止前重新定稿")
+
+	return nil
+}
+
+// GetDraftSubmissions 获取活动中尚未定稿的作品（主办方）
+func (s *SubmissionDraftService) GetDraftSubmissions(hackathonID uint64) (map[string]interface{}, error) {
+	var submissions []models.Submission
+	if err := database.DB.Preload("Team").Preload("Team.Leader").
+		Where("hackathon_id = ? AND draft = 1", hackathonID).
+		Order("updated_at DESC").
+		Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"list":          submissions,
+		"deadline":      nil,
+		"near_deadline": false,
+	}
+
+	var stage models.HackathonStage
+	if err := database.DB.Where("hackathon_id = ? AND stage = ?", hackathonID, "submission").First(&stage).Error; err == nil {
+		result["deadline"] = stage.EndTime
+		now := time.Now()
+		result["near_deadline"] = now.Before(stage.EndTime) && stage.EndTime.Sub(now) <= draftReminderWindow
+	}
+
+	return result, nil
+}
+
+// RunDeadlineReminders 临近提交截止时标记并提醒仍未定稿的作品（定时任务）
+func (s *SubmissionDraftService) RunDeadlineReminders() error {
+	now := time.Now()
+
+	var stages []models.HackathonStage
+	if err := database.DB.Joins("JOIN hackathons ON hackathons.id = hackathon_stages.hackathon_id").
+		Where("hackathon_stages.stage = ? AND hackathon_stages.end_time > ? AND hackathon_stages.end_time <= ?", "submission", now, now.Add(draftReminderWindow)).
+		Where("hackathons.status = ? AND hackathons.deleted_at IS NULL", "submission").
+		Find(&stages).Error; err != nil {
+		return err
+	}
+
+	channelService := &TeamChannelService{}
+	for _, stage := range stages {
+		var submissions []models.Submission
+		if err := database.DB.Where("hackathon_id = ? AND draft = 1 AND deadline_reminded_at IS NULL", stage.HackathonID).
+			Find(&submissions).Error; err != nil {
+			return err
+		}
+
+		for _, submission := range submissions {
+			if err := database.DB.Model(&submission).UpdateColumn("deadline_reminded_at", &now).Error; err != nil {
+				return err
+			}
+			channelService.PublishSystemEvent(submission.TeamID,
+				fmt.Sprintf("提交将于 %s 截止，作品尚未定稿，未定稿的作品不会参与投票", stage.EndTime.Format("2006-01-02 15:04")))
+		}
+	}
+	return nil
+}
+
+// loadForLeader 加载作品并检查队长权限和提交时间
+func (s *SubmissionDraftService) loadForLeader(submissionID, leaderID uint64) (*models.Submission, error) {
+	var submission models.Submission
+	if err := database.DB.Where("id = ?", submissionID).First(&submission).Error; err != nil {
+		return nil, errors.New("作品不存在")
+	}
+
+	var team models.Team
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", submission.TeamID).First(&team).Error; err != nil {
+		return nil, errors.New("队伍不存在")
+	}
+
+	if team.LeaderID != leaderID {
+		return nil, errors.New("只有队长可以操作")
+	}
+
+	if _, err := checkSubmissionWindow(submission.HackathonID); err != nil {
+		return nil, err
+	}
+
+	return &submission, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	submissionFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+	contractAddressPattern    = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+)
+
+// submissionFieldMaxLength 各类型自定义字段值的最大长度（字符数）
+var submissionFieldMaxLength = map[string]int{
+	"text":     255,
+	"textarea": 5000,
+	"url":      500,
+	"number":   50,
+	"select":   255,
+	"address":  42,
+}
+
+type SubmissionFieldService struct{}
+
+// GetFields 获取活动的自定义作品字段（按排序）
+func (s *SubmissionFieldService) GetFields(hackathonID uint64) ([]models.HackathonSubmissionField, error) {
+	var fields []models.HackathonSubmissionField
+	if err := database.DB.Where("hackathon_id = ?", hackathonID).Order("sort_order ASC, id ASC").Find(&fields).Error; err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// UpdateFields 整体替换活动的自定义作品字段（仅活动创建者，投票开始前）
+// requireRepoURL 不为空时同时设置定稿时是否必须填写代码仓库地址
+// 已提交作品中被删除字段的值保留在修改记录中，但不再展示
+func (s *SubmissionFieldService) UpdateFields(hackathonID uint64, fields []models.HackathonSubmissionField, requireRepoURL *bool, userID uint64, userRole string) error {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+
+	if userRole == "admin" {
+		return errors.New("Admin不能设置作品字段")
+	}
+	if hackathon.OrganizerID != userID {
+		return errors.New("只能设置自己创建的活动作品字段")
+	}
+	if hackathon.Status == "voting" || hackathon.Status == "results" {
+		return errors.New("投票开始后不能修改作品字段")
+	}
+
+	keys := make(map[string]bool)
+	for i := range fields {
+		field := &fields[i]
+		field.Key = strings.TrimSpace(field.Key)
+		field.Label = strings.TrimSpace(field.Label)
+		if !submissionFieldKeyPattern.MatchString(field.Key) {
+			return fmt.Errorf("字段标识 %q 格式不正确，只能包含小写字母、数字和下划线，且以字母开头", field.Key)
+		}
+		if keys[field.Key] {
+			return fmt.Errorf("字段标识 %s 重复", field.Key)
+		}
+		keys[field.Key] = true
+		if field.Label == "" {
+			return fmt.Errorf("字段 %s 的名称不能为空", field.Key)
+		}
+		if _, ok := submissionFieldMaxLength[field.Type]; !ok {
+			return fmt.Errorf("字段 %s 的类型无效", field.Key)
+		}
+		if field.Type == "select" {
+			if len(field.Options) == 0 {
+				return fmt.Errorf("下拉字段 %s 至少需要一个选项", field.Key)
+			}
+		} else {
+			field.Options = nil
+		}
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if requireRepoURL != nil {
+			if err := tx.Model(&hackathon).Update("require_repo_url", *requireRepoURL).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("hackathon_id = ?", hackathonID).Delete(&models.HackathonSubmissionField{}).Error; err != nil {
+			return err
+		}
+		for i := range fields {
+			fields[i].ID = 0
+			fields[i].HackathonID = hackathonID
+			if err := tx.Create(&fields[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FillCustomFieldList 按活动字段定义生成作品自定义字段的展示列表（已删除的字段不展示）
+func (s *SubmissionFieldService) FillCustomFieldList(hackathonID uint64, submissions []models.Submission) {
+	fields, err := s.GetFields(hackathonID)
+	if err != nil || len(fields) == 0 {
+		return
+	}
+	for i := range submissions {
+		list := make([]models.SubmissionCustomFieldValue, 0, len(fields))
+		for _, field := range fields {
+			value := submissions[i].CustomFields[field.Key]
+			if value == "" {
+				continue
+			}
+			list = append(list, models.SubmissionCustomFieldValue{Key: field.Key, Label: field.Label, Type: field.Type, Value: value})
+		}
+		submissions[i].CustomFieldList = list
+	}
+}
+
+// validateCustomFields 按活动字段定义校验并规范化作品自定义字段的值
+// 未定义的字段被忽略；必填项在定稿时检查，草稿允许留空
+func validateCustomFields(hackathonID uint64, values models.StringMap) (models.StringMap, error) {
+	if len(values) == 0 {
+		return values, nil
+	}
+
+	fieldService := &SubmissionFieldService{}
+	fields, err := fieldService.GetFields(hackathonID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(models.StringMap)
+	for _, field := range fields {
+		value := strings.TrimSpace(values[field.Key])
+		if value == "" {
+			continue
+		}
+		if utf8.RuneCountInString(value) > submissionFieldMaxLength[field.Type] {
+			return nil, fmt.Errorf("%s不能超过%d个字符", field.Label, submissionFieldMaxLength[field.Type])
+		}
+
+		switch field.Type {
+		case "url":
+			if err := validateSubmissionURL(field.Label, value); err != nil {
+				return nil, err
+			}
+		case "number":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("%s必须是数字", field.Label)
+			}
+		case "select":
+			valid := false
+			for _, option := range field.Options {
+				if option == value {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				return nil, fmt.Errorf("%s的值不在可选项中", field.Label)
+			}
+		case "address":
+			if !contractAddressPattern.MatchString(value) {
+				return nil, fmt.Errorf("%s格式不正确，必须是 0x 开头的 40 位十六进制地址", field.Label)
+			}
+		}
+		result[field.Key] = value
+	}
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+)
+
+// PublicModerationStatuses 公开展示并参与投票和排名的作品审核状态
+var PublicModerationStatuses = []string{"normal", "flagged"}
+
+// moderationStatusLabels 审核状态说明（用于队伍频道通知）
+var moderationStatusLabels = map[string]string{
+	"normal":       "恢复正常",
+	"flagged":      "标记为待核查",
+	"hidden":       "隐藏",
+	"disqualified": "取消参赛资格",
+}
+
+type SubmissionModerationService struct{}
+
+// GetSubmissionList 获取活动的全部作品（含草稿、已隐藏和已取消资格的作品，主办方）
+// status 为 draft/finalized 时按定稿状态筛选
+func (s *SubmissionModerationService) GetSubmissionList(hackathonID uint64, page, pageSize int, keyword, status, moderationStatus string) ([]models.Submission, int64, error) {
+	var submissions []models.Submission
+	var total int64
+
+	query := database.DB.Model(&models.Submission{}).Where("hackathon_id = ?", hackathonID)
+
+	if keyword != "" {
+		query = query.Where("name LIKE ?", "%"+keyword+"%")
+	}
+
+	switch status {
+	case "draft":
+		query = query.Where("draft = 1")
+	case "finalized":
+		query = query.Where("draft = 0")
+	}
+
+	if moderationStatus != "" {
+		query = query.Where("moderation_status = ?", moderationStatus)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Team").Preload("Team.Leader").
+		Order("created_at DESC").
+		Offset(offset).Limit(pageSize).Find(&submissions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return submissions, total, nil
+}
+
+// ModerateSubmission 设置作品审核状态（仅活动创建者）
+// 隐藏和取消资格必须填写原因，队伍可在作品详情中查看审核状态和原因
+func (s *SubmissionModerationService) ModerateSubmission(hackathonID, submissionID, userID uint64, status, reason string) error {
+	if _, ok := moderationStatusLabels[status]; !ok {
+		return errors.New("无效的审核状态")
+	}
+
+	reason = strings.TrimSpace(reason)
+	if (status == "hidden" || status == "disqualified") && reason == "" {
+		return errors.New("请填写原因")
+	}
+	if len([]rune(reason)) > 500 {
+		return errors.New("原因不能超过500个字符")
+	}
+
+	var submission models.Submission
+	if err := database.DB.Where("id = ? AND hackathon_id = ?", submissionID, hackathonID).First(&submission).Error; err != nil {
+		return errors.New("作品不存在")
+	}
+
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+	if hackathon.Status == "results" {
+		return errors.New("结果已公布，无法修改作品审核状态")
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"moderation_status": status,
+		"moderation_reason": reason,
+		"moderated_by":      &userID,
+		"moderated_at":      &now,
+	}
+	if status == "normal" {
+		updates["moderation_reason"] = ""
+	}
+	if err := database.DB.Model(&submission).UpdateColumns(updates).Error; err != nil {
+		return err
+	}
+
+	// 推送队伍频道系统事件
+	event := fmt.Sprintf("作品已被主办方%s", moderationStatusLabels[status])
+	if reason != "" && status != "normal" {
+		event += "，原因：" + reason
+	}
+	channelService := &TeamChannelService{}
+	channelService.PublishSystemEvent(submission.TeamID, event)
+
+	return nil
+}
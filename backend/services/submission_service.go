@@ -1,22 +1,42 @@
 package services
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 
+	"hackathon-backend/config"
 	"hackathon-backend/database"
 	"hackathon-backend/models"
+	"hackathon-backend/utils"
+
+	"gorm.io/gorm"
 )
 
 type SubmissionService struct{}
 
-// CreateSubmission 提交作品
+// CreateSubmission 提交作品（新作品保存为草稿）
 func (s *SubmissionService) CreateSubmission(hackathonID, teamID uint64, submission *models.Submission) error {
+	if err := validateSubmissionFields(submission); err != nil {
+		return err
+	}
+
 	// 检查活动状态
 	var hackathon models.Hackathon
 	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
 		return errors.New("活动不存在")
 	}
 
+	customFields, err := validateCustomFields(hackathonID, submission.CustomFields)
+	if err != nil {
+		return err
+	}
+	submission.CustomFields = customFields
+
 	if hackathon.Status != "submission" {
 		return errors.New("当前不在提交阶段")
 	}
@@ -37,32 +57,57 @@ func (s *SubmissionService) CreateSubmission(hackathonID, teamID uint64, submiss
 		return errors.New("队伍不存在")
 	}
 
+	// 检查队伍是否达到最小人数要求
+	teamService := &TeamService{}
+	if err := teamService.CheckMinTeamSize(&team, &hackathon); err != nil {
+		return err
+	}
+
+	channelService := &TeamChannelService{}
+
 	// 检查是否已有提交
 	var existing models.Submission
 	if err := database.DB.Where("hackathon_id = ? AND team_id = ?", hackathonID, teamID).First(&existing).Error; err == nil {
+		if err := checkSubmissionEditable(&existing); err != nil {
+			return err
+		}
+
 		// 更新现有提交
 		submission.ID = existing.ID
-		return database.DB.Model(&existing).Updates(submission).Error
+		if err := database.DB.Model(&existing).Updates(submission).Error; err != nil {
+			return err
+		}
+		channelService.PublishSystemEvent(teamID, "作品已更新")
+		return nil
 	}
 
-	// 创建新提交
+	// 创建新提交（保存为草稿，定稿后才会公开并参与投票）
 	submission.HackathonID = hackathonID
 	submission.TeamID = teamID
+	submission.Draft = 1
 
-	return database.DB.Create(submission).Error
+	if err := database.DB.Create(submission).Error; err != nil {
+		return err
+	}
+	channelService.PublishSystemEvent(teamID, "作品草稿已保存")
+	return nil
 }
 
 // GetSubmissionList 获取作品列表
-func (s *SubmissionService) GetSubmissionList(hackathonID uint64, page, pageSize int, keyword, sort string) ([]models.Submission, int64, error) {
+func (s *SubmissionService) GetSubmissionList(hackathonID uint64, page, pageSize int, keyword, techTag, sort string) ([]models.Submission, int64, error) {
 	var submissions []models.Submission
 	var total int64
 
-	query := database.DB.Model(&models.Submission{}).Where("hackathon_id = ? AND draft = 0", hackathonID)
+	query := database.DB.Model(&models.Submission{}).Where("hackathon_id = ? AND draft = 0 AND moderation_status IN ?", hackathonID, PublicModerationStatuses)
 
 	if keyword != "" {
 		query = query.Where("name LIKE ?", "%"+keyword+"%")
 	}
 
+	if techTag != "" {
+		query = query.Where("FIND_IN_SET(?, tech_stack) > 0", strings.TrimSpace(techTag))
+	}
+
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
@@ -80,28 +125,111 @@ func (s *SubmissionService) GetSubmissionList(hackathonID uint64, page, pageSize
 		return nil, 0, err
 	}
 
+	// 统计评论数
+	ids := make([]uint64, 0, len(submissions))
+	for _, submission := range submissions {
+		ids = append(ids, submission.ID)
+	}
+	commentService := &SubmissionCommentService{}
+	counts := commentService.CountComments(ids)
+	for i := range submissions {
+		submissions[i].CommentCount = counts[submissions[i].ID]
+	}
+
 	return submissions, total, nil
 }
 
+// CanViewSubmission 参赛者能否查看作品：已定稿且审核状态公开的作品所有人可见，草稿、已隐藏和已取消资格的作品仅队伍成员可见
+func (s *SubmissionService) CanViewSubmission(submission *models.Submission, participantID uint64) bool {
+	if isPublicSubmission(submission) {
+		return true
+	}
+	teamService := &TeamService{}
+	return teamService.IsTeamMember(submission.TeamID, participantID)
+}
+
+// isPublicSubmission 作品是否公开展示（已定稿且审核状态公开）
+func isPublicSubmission(submission *models.Submission) bool {
+	if submission.Draft != 0 {
+		return false
+	}
+	for _, status := range PublicModerationStatuses {
+		if submission.ModerationStatus == status {
+			return true
+		}
+	}
+	return false
+}
+
+// loadVisibleSubmission 加载参赛者可见的作品，同时返回参赛者是否为队伍成员
+func loadVisibleSubmission(submissionID, participantID uint64) (*models.Submission, bool, error) {
+	var submission models.Submission
+	if err := database.DB.Where("id = ?", submissionID).First(&submission).Error; err != nil {
+		return nil, false, errors.New("作品不存在")
+	}
+
+	teamService := &TeamService{}
+	isMember := teamService.IsTeamMember(submission.TeamID, participantID)
+	if !isMember && !isPublicSubmission(&submission) {
+		return nil, false, errors.New("作品不存在")
+	}
+
+	return &submission, isMember, nil
+}
+
 // GetSubmissionByID 根据ID获取作品详情
 func (s *SubmissionService) GetSubmissionByID(submissionID uint64) (*models.Submission, error) {
 	var submission models.Submission
 	if err := database.DB.Preload("Team").Preload("Team.Members").Preload("Team.Members.Participant").
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("LinkChecks").
 		Where("id = ?", submissionID).First(&submission).Error; err != nil {
 		return nil, err
 	}
 
-	return &submission, nil
+	attachmentService := &SubmissionAttachmentService{}
+	attachmentService.FillDownloadURLs(submission.Attachments)
+
+	commentService := &SubmissionCommentService{}
+	submission.CommentCount = commentService.CountComments([]uint64{submission.ID})[submission.ID]
+
+	fieldService := &SubmissionFieldService{}
+	submissions := []models.Submission{submission}
+	fieldService.FillCustomFieldList(submission.HackathonID, submissions)
+
+	return &submissions[0], nil
 }
 
 // UpdateSubmission 更新作品（提交阶段内）
 func (s *SubmissionService) UpdateSubmission(submissionID, teamID, participantID uint64, submission *models.Submission) error {
+	return s.updateSubmission(submissionID, teamID, participantID, submission, nil)
+}
+
+// updateSubmission 按更新作品的规则写入作品内容
+// restored 不为空时表示从修改记录恢复：所有内容字段（包括空值）都会被覆盖，附件列表同步恢复
+func (s *SubmissionService) updateSubmission(submissionID, teamID, participantID uint64, submission *models.Submission, restored *models.SubmissionHistory) error {
+	if err := validateSubmissionFields(submission); err != nil {
+		return err
+	}
+
 	// 检查作品是否存在
 	var existing models.Submission
 	if err := database.DB.Where("id = ? AND team_id = ?", submissionID, teamID).First(&existing).Error; err != nil {
 		return errors.New("作品不存在")
 	}
 
+	if err := checkSubmissionEditable(&existing); err != nil {
+		return err
+	}
+
+	customFields, err := validateCustomFields(existing.HackathonID, submission.CustomFields)
+	if err != nil {
+		return err
+	}
+	submission.CustomFields = customFields
+
 	// 检查活动状态
 	var hackathon models.Hackathon
 	if err := database.DB.Where("id = ? AND deleted_at IS NULL", existing.HackathonID).First(&hackathon).Error; err != nil {
@@ -122,20 +250,34 @@ func (s *SubmissionService) UpdateSubmission(submissionID, teamID, participantID
 		return errors.New("不在提交时间范围内")
 	}
 
-	// 保存修改记录
-	history := models.SubmissionHistory{
-		SubmissionID:  submissionID,
-		ParticipantID: participantID,
-		Name:          existing.Name,
-		Description:   existing.Description,
-		Link:          existing.Link,
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// 保存修改记录
+		if err := saveSubmissionHistory(tx, &existing, participantID); err != nil {
+			return err
+		}
+
+		// 更新作品
+		if restored == nil {
+			return tx.Model(&existing).Updates(submission).Error
+		}
+
+		if err := tx.Model(&existing).Select(submissionContentColumns).Updates(submission).Error; err != nil {
+			return err
+		}
+		return restoreAttachments(tx, submissionID, restored)
+	}); err != nil {
+		return err
 	}
-	if err := database.DB.Create(&history).Error; err != nil {
-		return errors.New("保存修改记录失败: " + err.Error())
+
+	// 推送队伍频道系统事件
+	event := participantDisplayName(participantID) + " 更新了作品"
+	if restored != nil {
+		event = fmt.Sprintf("%s 将作品恢复到了 %s 的版本", participantDisplayName(participantID), restored.CreatedAt.Format("2006-01-02 15:04:05"))
 	}
+	channelService := &TeamChannelService{}
+	channelService.PublishSystemEvent(teamID, event)
 
-	// 更新作品
-	return database.DB.Model(&existing).Updates(submission).Error
+	return nil
 }
 
 // GetSubmissionHistory 获取作品修改记录
@@ -147,6 +289,347 @@ func (s *SubmissionService) GetSubmissionHistory(submissionID uint64) ([]models.
 		Find(&histories).Error; err != nil {
 		return nil, err
 	}
+
+	// 解析附件快照并生成下载链接
+	attachmentService := &SubmissionAttachmentService{}
+	for i := range histories {
+		if err := decodeHistoryAttachments(&histories[i]); err == nil {
+			attachmentService.FillDownloadURLs(histories[i].AttachmentList)
+		}
+	}
 	return histories, nil
 }
 
+// saveSubmissionHistory 保存作品当前内容为一条修改记录
+func saveSubmissionHistory(tx *gorm.DB, submission *models.Submission, participantID uint64) error {
+	history, err := buildSubmissionHistory(tx, submission, participantID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Create(history).Error; err != nil {
+		return errors.New("保存修改记录失败: " + err.Error())
+	}
+	return nil
+}
+
+// buildSubmissionHistory 根据作品当前内容（含附件列表快照）生成修改记录
+func buildSubmissionHistory(tx *gorm.DB, submission *models.Submission, participantID uint64) (*models.SubmissionHistory, error) {
+	var attachments []models.SubmissionAttachment
+	if err := tx.Where("submission_id = ?", submission.ID).Order("created_at ASC").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	snapshot, err := json.Marshal(attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SubmissionHistory{
+		SubmissionID:  submission.ID,
+		ParticipantID: participantID,
+		Name:          submission.Name,
+		Description:   submission.Description,
+		Link:          submission.Link,
+		RepoURL:       submission.RepoURL,
+		DemoURL:       submission.DemoURL,
+		VideoURL:      submission.VideoURL,
+		TechStack:     submission.TechStack,
+		CoverImage:    submission.CoverImage,
+		Screenshots:   submission.Screenshots,
+		CustomFields:  submission.CustomFields,
+		Attachments:   string(snapshot),
+	}, nil
+}
+
+// checkSubmissionEditable 检查作品是否可以修改（已定稿的作品需先取消定稿）
+func checkSubmissionEditable(submission *models.Submission) error {
+	if submission.Draft == 0 {
+		return errors.New("作品已定稿，请先取消定稿再修改")
+	}
+	return nil
+}
+
+// checkSubmissionWindow 检查活动当前是否处于提交阶段时间内
+func checkSubmissionWindow(hackathonID uint64) (*models.Hackathon, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+
+	if hackathon.Status != "submission" {
+		return nil, errors.New("当前不在提交阶段")
+	}
+
+	hackathonService := &HackathonService{}
+	inTime, err := hackathonService.CheckStageTime(hackathonID, "submission")
+	if err != nil {
+		return nil, errors.New("提交阶段时间未设置")
+	}
+	if !inTime {
+		return nil, errors.New("不在提交时间范围内")
+	}
+
+	return &hackathon, nil
+}
+
+// maxSubmissionScreenshots 每个作品最多的截图数量
+const maxSubmissionScreenshots = 10
+
+// validateSubmissionFields 校验并规范化作品的链接和技术栈字段
+func validateSubmissionFields(submission *models.Submission) error {
+	// 定稿状态、审核状态、并列裁定和仓库快照由服务端维护，忽略客户端传入的值
+	submission.Draft = 0
+	submission.FinalizedAt = nil
+	submission.DeadlineRemindedAt = nil
+	submission.ModerationStatus = ""
+	submission.ModerationReason = ""
+	submission.ModeratedBy = nil
+	submission.ModeratedAt = nil
+	submission.TieBreakPriority = 0
+	submission.RepoHeadCommit = ""
+	submission.RepoCommitCount = 0
+	submission.RepoCommitsBeforeStart = 0
+	submission.RepoCommitsAfterEnd = 0
+	submission.RepoCheckStatus = ""
+	submission.RepoCheckMessage = ""
+	submission.RepoSnapshotAt = nil
+
+	submission.RepoURL = strings.TrimSpace(submission.RepoURL)
+	if err := validateRepoURL(submission.RepoURL); err != nil {
+		return err
+	}
+
+	urlFields := []struct {
+		label string
+		value *string
+	}{
+		{"作品链接", &submission.Link},
+		{"演示地址", &submission.DemoURL},
+		{"演示视频地址", &submission.VideoURL},
+		{"封面图片地址", &submission.CoverImage},
+	}
+	for _, field := range urlFields {
+		*field.value = strings.TrimSpace(*field.value)
+		if err := validateSubmissionURL(field.label, *field.value); err != nil {
+			return err
+		}
+	}
+
+	if len(submission.Screenshots) > maxSubmissionScreenshots {
+		return fmt.Errorf("截图最多%d张", maxSubmissionScreenshots)
+	}
+	for i := range submission.Screenshots {
+		submission.Screenshots[i] = strings.TrimSpace(submission.Screenshots[i])
+		if submission.Screenshots[i] == "" {
+			return errors.New("截图地址不能为空")
+		}
+		if err := validateSubmissionURL("截图地址", submission.Screenshots[i]); err != nil {
+			return err
+		}
+	}
+
+	submission.TechStack = normalizeSkills(submission.TechStack)
+	if len(submission.TechStack) > 255 {
+		return errors.New("技术栈标签过长")
+	}
+
+	return nil
+}
+
+// validateSubmissionURL 校验链接格式（仅允许 http/https，空值跳过）
+func validateSubmissionURL(label, value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > 500 {
+		return fmt.Errorf("%s不能超过500个字符", label)
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s格式不正确，必须是 http 或 https 链接", label)
+	}
+	return nil
+}
+
+// submissionContentColumns 作品内容字段（恢复历史版本时整体覆盖）
+var submissionContentColumns = []string{"name", "description", "link", "repo_url", "demo_url", "video_url", "tech_stack", "cover_image", "screenshots", "custom_fields"}
+
+// SubmissionFieldChange 作品字段差异
+type SubmissionFieldChange struct {
+	Field string           `json:"field"`
+	Old   interface{}      `json:"old"`
+	New   interface{}      `json:"new"`
+	Lines []utils.DiffLine `json:"lines,omitempty"` // 作品描述的行级差异
+}
+
+// SubmissionDiff 两个作品版本之间的差异
+type SubmissionDiff struct {
+	From    string                  `json:"from"`
+	To      string                  `json:"to"`
+	Changes []SubmissionFieldChange `json:"changes"`
+}
+
+// DiffSubmissionVersions 比较作品的两个版本
+// 版本为修改记录ID，或 current 表示作品当前内容
+func (s *SubmissionService) DiffSubmissionVersions(submissionID uint64, from, to string) (*SubmissionDiff, error) {
+	oldVersion, err := s.loadSubmissionVersion(submissionID, from)
+	if err != nil {
+		return nil, err
+	}
+	newVersion, err := s.loadSubmissionVersion(submissionID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &SubmissionDiff{From: from, To: to, Changes: make([]SubmissionFieldChange, 0)}
+	addChange := func(field string, oldValue, newValue string) {
+		if oldValue != newValue {
+			diff.Changes = append(diff.Changes, SubmissionFieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	addChange("name", oldVersion.Name, newVersion.Name)
+	if oldVersion.Description != newVersion.Description {
+		diff.Changes = append(diff.Changes, SubmissionFieldChange{
+			Field: "description",
+			Old:   oldVersion.Description,
+			New:   newVersion.Description,
+			Lines: utils.DiffLines(oldVersion.Description, newVersion.Description),
+		})
+	}
+	addChange("link", oldVersion.Link, newVersion.Link)
+	addChange("repo_url", oldVersion.RepoURL, newVersion.RepoURL)
+	addChange("demo_url", oldVersion.DemoURL, newVersion.DemoURL)
+	addChange("video_url", oldVersion.VideoURL, newVersion.VideoURL)
+	addChange("tech_stack", oldVersion.TechStack, newVersion.TechStack)
+	addChange("cover_image", oldVersion.CoverImage, newVersion.CoverImage)
+
+	if strings.Join(oldVersion.Screenshots, "\n") != strings.Join(newVersion.Screenshots, "\n") {
+		diff.Changes = append(diff.Changes, SubmissionFieldChange{Field: "screenshots", Old: oldVersion.Screenshots, New: newVersion.Screenshots})
+	}
+
+	customKeys := make(map[string]bool)
+	for key := range oldVersion.CustomFields {
+		customKeys[key] = true
+	}
+	for key := range newVersion.CustomFields {
+		customKeys[key] = true
+	}
+	sortedKeys := make([]string, 0, len(customKeys))
+	for key := range customKeys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	for _, key := range sortedKeys {
+		addChange("custom_fields."+key, oldVersion.CustomFields[key], newVersion.CustomFields[key])
+	}
+
+	oldFiles, newFiles := attachmentNames(oldVersion.AttachmentList), attachmentNames(newVersion.AttachmentList)
+	if strings.Join(oldFiles, "\n") != strings.Join(newFiles, "\n") {
+		diff.Changes = append(diff.Changes, SubmissionFieldChange{Field: "attachments", Old: oldFiles, New: newFiles})
+	}
+
+	return diff, nil
+}
+
+// RestoreSubmissionVersion 将作品恢复到指定修改记录的版本（按更新作品的规则，仅提交阶段内）
+func (s *SubmissionService) RestoreSubmissionVersion(submissionID, teamID, historyID, participantID uint64) error {
+	var history models.SubmissionHistory
+	if err := database.DB.Where("id = ? AND submission_id = ?", historyID, submissionID).First(&history).Error; err != nil {
+		return errors.New("修改记录不存在")
+	}
+	if err := decodeHistoryAttachments(&history); err != nil {
+		return errors.New("修改记录的附件快照已损坏")
+	}
+
+	submission := models.Submission{
+		Name:         history.Name,
+		Description:  history.Description,
+		Link:         history.Link,
+		RepoURL:      history.RepoURL,
+		DemoURL:      history.DemoURL,
+		VideoURL:     history.VideoURL,
+		TechStack:    history.TechStack,
+		CoverImage:   history.CoverImage,
+		Screenshots:  history.Screenshots,
+		CustomFields: history.CustomFields,
+	}
+	return s.updateSubmission(submissionID, teamID, participantID, &submission, &history)
+}
+
+// loadSubmissionVersion 加载作品的某个版本，统一表示为修改记录
+func (s *SubmissionService) loadSubmissionVersion(submissionID uint64, version string) (*models.SubmissionHistory, error) {
+	if version == "" || version == "current" {
+		var submission models.Submission
+		if err := database.DB.Where("id = ?", submissionID).First(&submission).Error; err != nil {
+			return nil, errors.New("作品不存在")
+		}
+		history, err := buildSubmissionHistory(database.DB, &submission, 0)
+		if err != nil {
+			return nil, err
+		}
+		history.CreatedAt = submission.UpdatedAt
+		if err := decodeHistoryAttachments(history); err != nil {
+			return nil, err
+		}
+		return history, nil
+	}
+
+	historyID, err := strconv.ParseUint(version, 10, 64)
+	if err != nil {
+		return nil, errors.New("无效的版本: " + version)
+	}
+	var history models.SubmissionHistory
+	if err := database.DB.Where("id = ? AND submission_id = ?", historyID, submissionID).First(&history).Error; err != nil {
+		return nil, errors.New("修改记录不存在")
+	}
+	if err := decodeHistoryAttachments(&history); err != nil {
+		return nil, errors.New("修改记录的附件快照已损坏")
+	}
+	return &history, nil
+}
+
+// restoreAttachments 将作品附件恢复为修改记录中的附件列表
+func restoreAttachments(tx *gorm.DB, submissionID uint64, history *models.SubmissionHistory) error {
+	ids := make([]uint64, 0, len(history.AttachmentList))
+	for _, attachment := range history.AttachmentList {
+		ids = append(ids, attachment.ID)
+	}
+
+	removeQuery := tx.Where("submission_id = ?", submissionID)
+	if len(ids) > 0 {
+		if err := tx.Unscoped().Model(&models.SubmissionAttachment{}).
+			Where("submission_id = ? AND id IN ?", submissionID, ids).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		removeQuery = removeQuery.Where("id NOT IN ?", ids)
+	}
+	return removeQuery.Delete(&models.SubmissionAttachment{}).Error
+}
+
+// decodeHistoryAttachments 解析修改记录中的附件快照
+func decodeHistoryAttachments(history *models.SubmissionHistory) error {
+	if history.Attachments == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(history.Attachments), &history.AttachmentList)
+}
+
+func attachmentNames(attachments []models.SubmissionAttachment) []string {
+	names := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		names = append(names, attachment.FileName)
+	}
+	return names
+}
+
+// validateRepoURL 校验代码仓库地址（配置允许时可使用 file:// 本地仓库）
+func validateRepoURL(value string) error {
+	if config.AppConfig.GitAllowFileURL && strings.HasPrefix(value, "file://") {
+		if len(value) > 500 {
+			return errors.New("代码仓库地址不能超过500个字符")
+		}
+		return nil
+	}
+	return validateSubmissionURL("代码仓库地址", value)
+}
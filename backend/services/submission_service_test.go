@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+
+	"hackathon-backend/models"
+)
+
+func TestIsPublicSubmission(t *testing.T) {
+	cases := []struct {
+		draft  int
+		status string
+		want   bool
+	}{
+		{0, "normal", true},
+		{0, "flagged", true},
+		{0, "hidden", false},
+		{0, "disqualified", false},
+		{1, "normal", false},
+		{1, "flagged", false},
+	}
+	for _, c := range cases {
+		submission := &models.Submission{Draft: c.draft, ModerationStatus: c.status}
+		if got := isPublicSubmission(submission); got != c.want {
+			t.Errorf("draft=%d status=%s 时公开状态为 %v，期望 %v", c.draft, c.status, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type TeamBoardService struct{}
+
+// CreateOrUpdatePost 发布或更新求组队帖子（已签到且未组队的参赛者）
+func (s *TeamBoardService) CreateOrUpdatePost(hackathonID, participantID uint64, pitch, skills string) (*models.TeamSeekingPost, error) {
+	pitch = strings.TrimSpace(pitch)
+	if pitch == "" {
+		return nil, errors.New("自我介绍不能为空")
+	}
+	if utf8.RuneCountInString(pitch) > 500 {
+		return nil, errors.New("自我介绍不能超过500个字符")
+	}
+	skills = normalizeSkills(skills)
+	if len(skills) > 255 {
+		return nil, errors.New("技能标签过长")
+	}
+
+	// 检查活动状态
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+
+	if hackathon.Status != "team_formation" {
+		return nil, errors.New("当前不在组队阶段")
+	}
+
+	// 检查是否已签到
+	registrationService := &RegistrationService{}
+	checkedIn, _, err := registrationService.GetCheckinStatus(hackathonID, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if !checkedIn {
+		return nil, errors.New("请先完成签到")
+	}
+
+	// 检查是否已在队伍中
+	teamService := &TeamService{}
+	if team, _ := teamService.GetUserTeam(hackathonID, participantID); team != nil {
+		return nil, errors.New("您已经在队伍中")
+	}
+
+	// 已有帖子则更新并重新开放
+	var post models.TeamSeekingPost
+	if err := database.DB.Where("hackathon_id = ? AND participant_id = ?", hackathonID, participantID).First(&post).Error; err == nil {
+		if err := database.DB.Model(&post).Updates(map[string]interface{}{
+			"pitch":  pitch,
+			"skills": skills,
+			"status": "open",
+		}).Error; err != nil {
+			return nil, err
+		}
+		return &post, nil
+	}
+
+	post = models.TeamSeekingPost{
+		HackathonID:   hackathonID,
+		ParticipantID: participantID,
+		Pitch:         pitch,
+		Skills:        skills,
+		Status:        "open",
+	}
+	if err := database.DB.Create(&post).Error; err != nil {
+		return nil, errors.New("发布失败: " + err.Error())
+	}
+
+	return &post, nil
+}
+
+// ClosePost 关闭自己的求组队帖子
+func (s *TeamBoardService) ClosePost(hackathonID, participantID uint64) error {
+	result := database.DB.Model(&models.TeamSeekingPost{}).
+		Where("hackathon_id = ? AND participant_id = ? AND status = ?", hackathonID, participantID, "open").
+		Update("status", "closed")
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("帖子不存在或已关闭")
+	}
+	return nil
+}
+
+// GetMyPost 获取自己在活动中的求组队帖子
+func (s *TeamBoardService) GetMyPost(hackathonID, participantID uint64) (*models.TeamSeekingPost, error) {
+	var post models.TeamSeekingPost
+	if err := database.DB.Where("hackathon_id = ? AND participant_id = ?", hackathonID, participantID).First(&post).Error; err != nil {
+		return nil, nil // 未发布帖子，返回 nil 而不是错误
+	}
+	return &post, nil
+}
+
+// GetPostList 获取活动中开放的求组队帖子列表
+func (s *TeamBoardService) GetPostList(hackathonID uint64, page, pageSize int, keyword, skill string) ([]models.TeamSeekingPost, int64, error) {
+	var posts []models.TeamSeekingPost
+	var total int64
+
+	query := database.DB.Model(&models.TeamSeekingPost{}).Where("hackathon_id = ? AND status = ?", hackathonID, "open")
+
+	if keyword != "" {
+		query = query.Where("pitch LIKE ?", "%"+keyword+"%")
+	}
+
+	if skill != "" {
+		query = query.Where("FIND_IN_SET(?, skills) > 0", strings.TrimSpace(skill))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Participant").
+		Order("updated_at DESC").
+		Offset(offset).Limit(pageSize).Find(&posts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return posts, total, nil
+}
+
+// SendInvitation 队长向求组队的参赛者发送邀请
+func (s *TeamBoardService) SendInvitation(postID, leaderID uint64, message string) (*models.TeamInvitation, error) {
+	if utf8.RuneCountInString(message) > 500 {
+		return nil, errors.New("邀请留言不能超过500个字符")
+	}
+
+	var post models.TeamSeekingPost
+	if err := database.DB.Where("id = ?", postID).First(&post).Error; err != nil {
+		return nil, errors.New("帖子不存在")
+	}
+
+	if post.Status != "open" {
+		return nil, errors.New("该参赛者已不在求组队状态")
+	}
+
+	// 检查邀请人是否是该活动中某支队伍的队长
+	var team models.Team
+	if err := database.DB.Where("hackathon_id = ? AND leader_id = ? AND deleted_at IS NULL", post.HackathonID, leaderID).First(&team).Error; err != nil {
+		return nil, errors.New("只有队长可以发送邀请")
+	}
+
+	if team.Status != "recruiting" {
+		return nil, errors.New("队伍已锁定，无法邀请")
+	}
+
+	// 检查队伍是否已满
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", post.HackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+	teamService := &TeamService{}
+	var memberCount int64
+	database.DB.Model(&models.TeamMember{}).Where("team_id = ?", team.ID).Count(&memberCount)
+	if int(memberCount) >= teamService.TeamCapacity(&team, &hackathon) {
+		return nil, errors.New("队伍已满")
+	}
+
+	// 检查是否已有待处理的邀请
+	var existing models.TeamInvitation
+	if err := database.DB.Where("team_id = ? AND participant_id = ? AND status = ?", team.ID, post.ParticipantID, "pending").First(&existing).Error; err == nil {
+		return nil, errors.New("已向该参赛者发送过邀请")
+	}
+
+	invitation := models.TeamInvitation{
+		HackathonID:   post.HackathonID,
+		TeamID:        team.ID,
+		ParticipantID: post.ParticipantID,
+		InviterID:     leaderID,
+		Message:       strings.TrimSpace(message),
+		Status:        "pending",
+	}
+	if err := database.DB.Create(&invitation).Error; err != nil {
+		return nil, errors.New("发送邀请失败: " + err.Error())
+	}
+
+	return &invitation, nil
+}
+
+// GetMyInvitations 获取参赛者在活动中收到的邀请
+func (s *TeamBoardService) GetMyInvitations(hackathonID, participantID uint64) ([]models.TeamInvitation, error) {
+	var invitations []models.TeamInvitation
+	if err := database.DB.Preload("Team").Preload("Team.Leader").
+		Where("hackathon_id = ? AND participant_id = ?", hackathonID, participantID).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// GetTeamInvitations 获取队伍发出的邀请（仅队长）
+func (s *TeamBoardService) GetTeamInvitations(teamID, leaderID uint64) ([]models.TeamInvitation, error) {
+	var team models.Team
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", teamID).First(&team).Error; err != nil {
+		return nil, errors.New("队伍不存在")
+	}
+
+	if team.LeaderID != leaderID {
+		return nil, errors.New("只有队长可以查看队伍邀请")
+	}
+
+	var invitations []models.TeamInvitation
+	if err := database.DB.Preload("Participant").
+		Where("team_id = ?", teamID).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// RespondInvitation 接受或拒绝邀请
+// 接受邀请时通过 JoinTeam 加入队伍，加入成功后帖子自动关闭
+func (s *TeamBoardService) RespondInvitation(invitationID, participantID uint64, accept bool) error {
+	var invitation models.TeamInvitation
+	if err := database.DB.Where("id = ? AND participant_id = ?", invitationID, participantID).First(&invitation).Error; err != nil {
+		return errors.New("邀请不存在")
+	}
+
+	if invitation.Status != "pending" {
+		return errors.New("邀请已处理")
+	}
+
+	if !accept {
+		return database.DB.Model(&invitation).Update("status", "declined").Error
+	}
+
+	teamService := &TeamService{}
+	if err := teamService.JoinTeam(invitation.TeamID, participantID); err != nil {
+		return err
+	}
+
+	return database.DB.Model(&invitation).Update("status", "accepted").Error
+}
+
+// CloseForParticipant 参赛者加入或创建队伍后，关闭其求组队帖子并使待处理的邀请失效
+func (s *TeamBoardService) CloseForParticipant(tx *gorm.DB, hackathonID, participantID uint64) error {
+	if err := tx.Model(&models.TeamSeekingPost{}).
+		Where("hackathon_id = ? AND participant_id = ? AND status = ?", hackathonID, participantID, "open").
+		Update("status", "closed").Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.TeamInvitation{}).
+		Where("hackathon_id = ? AND participant_id = ? AND status = ?", hackathonID, participantID, "pending").
+		Update("status", "expired").Error
+}
+
+// normalizeSkills 规范化技能标签（去除空白和重复项，逗号分隔）
+func normalizeSkills(skills string) string {
+	seen := make(map[string]bool)
+	result := make([]string, 0)
+	for _, skill := range strings.Split(strings.ReplaceAll(skills, "，", ","), ",") {
+		skill = strings.TrimSpace(skill)
+		if skill == "" || seen[strings.ToLower(skill)] {
+			continue
+		}
+		seen[strings.ToLower(skill)] = true
+		result = append(result, skill)
+	}
+	return strings.Join(result, ",")
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+)
+
+// 队伍频道推送的事件类型
+const (
+	TeamChannelEventMessage  = "message"  // 成员消息
+	TeamChannelEventSystem   = "system"   // 系统事件（成员加入/退出、作品更新等）
+	TeamChannelEventPresence = "presence" // 在线成员变化
+)
+
+// 单条消息最大长度（字符）
+const teamMessageMaxLength = 2000
+
+// TeamChannelEvent 队伍频道推送事件
+type TeamChannelEvent struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// TeamChannelClient 队伍频道连接
+// Send 由频道写入，连接断开或被移出队伍时关闭
+type TeamChannelClient struct {
+	TeamID        uint64
+	ParticipantID uint64
+	Send          chan []byte
+}
+
+// teamChannelHub 保存所有在线连接（按队伍分组）
+type teamChannelHub struct {
+	mu      sync.RWMutex
+	clients map[uint64]map[*TeamChannelClient]struct{}
+}
+
+var channelHub = &teamChannelHub{
+	clients: make(map[uint64]map[*TeamChannelClient]struct{}),
+}
+
+type TeamChannelService struct{}
+
+// IsTeamMember 检查参赛者是否是队伍成员
+func (s *TeamChannelService) IsTeamMember(teamID, participantID uint64) bool {
+	var count int64
+	database.DB.Model(&models.TeamMember{}).
+		Joins("JOIN teams ON team_members.team_id = teams.id").
+		Where("team_members.team_id = ? AND team_members.participant_id = ? AND teams.deleted_at IS NULL", teamID, participantID).
+		Count(&count)
+	return count > 0
+}
+
+// Register 注册连接并广播在线成员变化
+func (s *TeamChannelService) Register(teamID, participantID uint64) *TeamChannelClient {
+	client := &TeamChannelClient{
+		TeamID:        teamID,
+		ParticipantID: participantID,
+		Send:          make(chan []byte, 32),
+	}
+
+	channelHub.mu.Lock()
+	if channelHub.clients[teamID] == nil {
+		channelHub.clients[teamID] = make(map[*TeamChannelClient]struct{})
+	}
+	channelHub.clients[teamID][client] = struct{}{}
+	channelHub.mu.Unlock()
+
+	s.broadcastPresence(teamID)
+	return client
+}
+
+// Unregister 注销连接并广播在线成员变化
+func (s *TeamChannelService) Unregister(client *TeamChannelClient) {
+	if s.remove(client) {
+		s.broadcastPresence(client.TeamID)
+	}
+}
+
+// Disconnect 断开参赛者在队伍频道中的所有连接（成员退出或被移除时调用）
+func (s *TeamChannelService) Disconnect(teamID, participantID uint64) {
+	channelHub.mu.RLock()
+	var targets []*TeamChannelClient
+	for client := range channelHub.clients[teamID] {
+		if client.ParticipantID == participantID {
+			targets = append(targets, client)
+		}
+	}
+	channelHub.mu.RUnlock()
+
+	removed := false
+	for _, client := range targets {
+		if s.remove(client) {
+			removed = true
+		}
+	}
+	if removed {
+		s.broadcastPresence(teamID)
+	}
+}
+
+// DisconnectTeam 断开队伍频道中的所有连接（队伍解散时调用）
+func (s *TeamChannelService) DisconnectTeam(teamID uint64) {
+	channelHub.mu.RLock()
+	var targets []*TeamChannelClient
+	for client := range channelHub.clients[teamID] {
+		targets = append(targets, client)
+	}
+	channelHub.mu.RUnlock()
+
+	for _, client := range targets {
+		s.remove(client)
+	}
+}
+
+// OnlineMembers 获取队伍当前在线成员ID
+func (s *TeamChannelService) OnlineMembers(teamID uint64) []uint64 {
+	channelHub.mu.RLock()
+	defer channelHub.mu.RUnlock()
+
+	seen := make(map[uint64]bool)
+	online := make([]uint64, 0)
+	for client := range channelHub.clients[teamID] {
+		if !seen[client.ParticipantID] {
+			seen[client.ParticipantID] = true
+			online = append(online, client.ParticipantID)
+		}
+	}
+	return online
+}
+
+// SendMessage 发送成员消息（持久化后广播）
+func (s *TeamChannelService) SendMessage(teamID, participantID uint64, content string) (*models.TeamMessage, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("消息内容不能为空")
+	}
+	if utf8.RuneCountInString(content) > teamMessageMaxLength {
+		return nil, errors.New("消息内容过长")
+	}
+
+	if !s.IsTeamMember(teamID, participantID) {
+		return nil, errors.New("您不是该队伍成员")
+	}
+
+	message := models.TeamMessage{
+		TeamID:        teamID,
+		ParticipantID: &participantID,
+		Type:          TeamChannelEventMessage,
+		Content:       content,
+	}
+	if err := database.DB.Create(&message).Error; err != nil {
+		return nil, errors.New("发送消息失败: " + err.Error())
+	}
+
+	database.DB.Preload("Participant").First(&message, message.ID)
+	s.broadcast(teamID, TeamChannelEvent{Type: TeamChannelEventMessage, Data: message})
+	return &message, nil
+}
+
+// PublishSystemEvent 发布系统事件（持久化后广播）
+// 系统事件不影响主流程，失败时仅记录日志
+func (s *TeamChannelService) PublishSystemEvent(teamID uint64, content string) {
+	message := models.TeamMessage{
+		TeamID:  teamID,
+		Type:    TeamChannelEventSystem,
+		Content: content,
+	}
+	if err := database.DB.Create(&message).Error; err != nil {
+		log.Printf("保存队伍系统消息失败: team_id=%d, err=%v", teamID, err)
+		return
+	}
+
+	s.broadcast(teamID, TeamChannelEvent{Type: TeamChannelEventSystem, Data: message})
+}
+
+// GetMessages 获取队伍消息记录（按时间倒序分页）
+func (s *TeamChannelService) GetMessages(teamID uint64, page, pageSize int) ([]models.TeamMessage, int64, error) {
+	var messages []models.TeamMessage
+	var total int64
+
+	query := database.DB.Model(&models.TeamMessage{}).Where("team_id = ?", teamID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Participant").
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(pageSize).Find(&messages).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return messages, total, nil
+}
+
+// participantDisplayName 获取参赛者展示名称（昵称优先，否则使用钱包地址前缀）
+func participantDisplayName(participantID uint64) string {
+	var participant models.Participant
+	if err := database.DB.Where("id = ?", participantID).First(&participant).Error; err != nil {
+		return "未知成员"
+	}
+	if participant.Nickname != "" {
+		return participant.Nickname
+	}
+	if len(participant.WalletAddress) >= 8 {
+		return participant.WalletAddress[:8] + "..."
+	}
+	return participant.WalletAddress
+}
+
+// Notify 仅向指定连接推送事件（连接已断开或缓冲已满时忽略）
+func (s *TeamChannelService) Notify(client *TeamChannelClient, event TeamChannelEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	channelHub.mu.RLock()
+	defer channelHub.mu.RUnlock()
+	if _, ok := channelHub.clients[client.TeamID][client]; !ok {
+		return
+	}
+	select {
+	case client.Send <- payload:
+	default:
+	}
+}
+
+// remove 从频道中移除连接并关闭发送通道，返回是否确实移除
+func (s *TeamChannelService) remove(client *TeamChannelClient) bool {
+	channelHub.mu.Lock()
+	defer channelHub.mu.Unlock()
+
+	clients := channelHub.clients[client.TeamID]
+	if _, ok := clients[client]; !ok {
+		return false
+	}
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(channelHub.clients, client.TeamID)
+	}
+	close(client.Send)
+	return true
+}
+
+// broadcastPresence 广播队伍在线成员列表
+func (s *TeamChannelService) broadcastPresence(teamID uint64) {
+	s.broadcast(teamID, TeamChannelEvent{
+		Type: TeamChannelEventPresence,
+		Data: map[string]interface{}{
+			"online": s.OnlineMembers(teamID),
+		},
+	})
+}
+
+// broadcast 向队伍所有在线连接推送事件，发送缓冲已满的连接会被断开
+func (s *TeamChannelService) broadcast(teamID uint64, event TeamChannelEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("序列化队伍频道事件失败: %v", err)
+		return
+	}
+
+	var slow []*TeamChannelClient
+	channelHub.mu.RLock()
+	for client := range channelHub.clients[teamID] {
+		select {
+		case client.Send <- payload:
+		default:
+			slow = append(slow, client)
+		}
+	}
+	channelHub.mu.RUnlock()
+
+	for _, client := range slow {
+		s.remove(client)
+	}
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type TeamMergeService struct{}
+
+// RequestMerge 发起队伍合并申请（任一方队长发起，发起方视为已同意）
+// survivingTeamID 指定合并后保留的队伍，另一支队伍将被解散
+func (s *TeamMergeService) RequestMerge(teamID, otherTeamID, survivingTeamID, leaderID uint64, message string) (*models.TeamMergeRequest, error) {
+	if teamID == otherTeamID {
+		return nil, errors.New("不能与自己的队伍合并")
+	}
+	if survivingTeamID != teamID && survivingTeamID != otherTeamID {
+		return nil, errors.New("保留的队伍必须是参与合并的队伍之一")
+	}
+	if utf8.RuneCountInString(message) > 500 {
+		return nil, errors.New("申请留言不能超过500个字符")
+	}
+
+	var team models.Team
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", teamID).First(&team).Error; err != nil {
+		return nil, errors.New("队伍不存在")
+	}
+
+	if team.LeaderID != leaderID {
+		return nil, errors.New("只有队长可以发起合并申请")
+	}
+
+	var otherTeam models.Team
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", otherTeamID).First(&otherTeam).Error; err != nil {
+		return nil, errors.New("对方队伍不存在")
+	}
+
+	if team.HackathonID != otherTeam.HackathonID {
+		return nil, errors.New("只能与同一活动中的队伍合并")
+	}
+
+	source, target := &otherTeam, &team
+	if survivingTeamID == otherTeamID {
+		source, target = &team, &otherTeam
+	}
+
+	if err := s.checkMergeable(database.DB, source, target); err != nil {
+		return nil, err
+	}
+
+	// 检查两支队伍之间是否已有待处理的申请
+	var existing models.TeamMergeRequest
+	if err := database.DB.Where("status = ? AND ((source_team_id = ? AND target_team_id = ?) OR (source_team_id = ? AND target_team_id = ?))",
+		"pending", team.ID, otherTeam.ID, otherTeam.ID, team.ID).First(&existing).Error; err == nil {
+		return nil, errors.New("两支队伍之间已有待处理的合并申请")
+	}
+
+	request := models.TeamMergeRequest{
+		HackathonID:    team.HackathonID,
+		SourceTeamID:   source.ID,
+		TargetTeamID:   target.ID,
+		SourceTeamName: source.Name,
+		TargetTeamName: target.Name,
+		RequesterID:    leaderID,
+		Message:        strings.TrimSpace(message),
+		SourceApproved: source.ID == team.ID,
+		TargetApproved: target.ID == team.ID,
+		Status:         "pending",
+	}
+	if err := database.DB.Create(&request).Error; err != nil {
+		return nil, errors.New("发起合并申请失败: " + err.Error())
+	}
+
+	return &request, nil
+}
+
+// GetTeamMergeRequests 获取队伍相关的合并申请（仅队长）
+func (s *TeamMergeService) GetTeamMergeRequests(teamID, leaderID uint64) ([]models.TeamMergeRequest, error) {
+	var team models.Team
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", teamID).First(&team).Error; err != nil {
+		return nil, errors.New("队伍不存在")
+	}
+
+	if team.LeaderID != leaderID {
+		return nil, errors.New("只有队长可以查看合并申请")
+	}
+
+	var requests []models.TeamMergeRequest
+	if err := database.DB.Where("source_team_id = ? OR target_team_id = ?", teamID, teamID).
+		Order("created_at DESC").
+		Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ApproveMerge 同意合并申请，双方都同意后执行合并
+func (s *TeamMergeService) ApproveMerge(requestID, leaderID uint64) (*models.TeamMergeRequest, error) {
+	var request models.TeamMergeRequest
+	if err := database.DB.Where("id = ?", requestID).First(&request).Error; err != nil {
+		return nil, errors.New("合并申请不存在")
+	}
+
+	if request.Status != "pending" {
+		return nil, errors.New("合并申请已处理")
+	}
+
+	side, err := s.leaderSide(&request, leaderID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if side == "source" {
+		if request.SourceApproved {
+			return nil, errors.New("您已同意该合并申请")
+		}
+		request.SourceApproved = true
+		updates["source_approved"] = true
+	} else {
+		if request.TargetApproved {
+			return nil, errors.New("您已同意该合并申请")
+		}
+		request.TargetApproved = true
+		updates["target_approved"] = true
+	}
+
+	if !request.SourceApproved || !request.TargetApproved {
+		if err := database.DB.Model(&request).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+		return &request, nil
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return s.executeMerge(tx, &request)
+	}); err != nil {
+		return nil, err
+	}
+
+	// 推送队伍频道系统事件
+	channelService := &TeamChannelService{}
+	channelService.DisconnectTeam(request.SourceTeamID)
+	channelService.PublishSystemEvent(request.TargetTeamID, fmt.Sprintf("队伍 %s 已并入本队", request.SourceTeamName))
+
+	return &request, nil
+}
+
+// RejectMerge 拒绝或撤回合并申请（任一方队长）
+func (s *TeamMergeService) RejectMerge(requestID, leaderID uint64) error {
+	var request models.TeamMergeRequest
+	if err := database.DB.Where("id = ?", requestID).First(&request).Error; err != nil {
+		return errors.New("合并申请不存在")
+	}
+
+	if request.Status != "pending" {
+		return errors.New("合并申请已处理")
+	}
+
+	if _, err := s.leaderSide(&request, leaderID); err != nil {
+		return err
+	}
+
+	status := "rejected"
+	if request.RequesterID == leaderID {
+		status = "cancelled"
+	}
+	return database.DB.Model(&request).Update("status", status).Error
+}
+
+// executeMerge 执行合并：成员转入保留队伍，保留被合并队伍的作品记录，解散被合并队伍
+func (s *TeamMergeService) executeMerge(tx *gorm.DB, request *models.TeamMergeRequest) error {
+	var source, target models.Team
+	if err := tx.Where("id = ? AND deleted_at IS NULL", request.SourceTeamID).First(&source).Error; err != nil {
+		return errors.New("被合并的队伍不存在")
+	}
+	if err := tx.Where("id = ? AND deleted_at IS NULL", request.TargetTeamID).First(&target).Error; err != nil {
+		return errors.New("保留的队伍不存在")
+	}
+
+	if err := s.checkMergeable(tx, &source, &target); err != nil {
+		return err
+	}
+
+	// 成员转入保留队伍（原队长转为普通成员）
+	if err := tx.Model(&models.TeamMember{}).Where("team_id = ?", source.ID).
+		Updates(map[string]interface{}{"team_id": target.ID, "role": "member"}).Error; err != nil {
+		return fmt.Errorf("转移成员失败: %w", err)
+	}
+
+	// 合并后人数超过保留队伍自身上限时，放宽队伍上限
+	var memberCount int64
+	tx.Model(&models.TeamMember{}).Where("team_id = ?", target.ID).Count(&memberCount)
+	if int(memberCount) > target.MaxSize {
+		if err := tx.Model(&target).Update("max_size", int(memberCount)).Error; err != nil {
+			return err
+		}
+	}
+
+	// 保留被合并队伍的作品
+	if err := s.preserveSubmission(tx, &source, &target); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := tx.Model(request).Updates(map[string]interface{}{
+		"source_approved": true,
+		"target_approved": true,
+		"status":          "completed",
+		"completed_at":    &now,
+	}).Error; err != nil {
+		return err
+	}
+	request.Status = "completed"
+	request.CompletedAt = &now
+
+	// 解散被合并队伍
+	teamService := &TeamService{}
+	return teamService.purgeTeam(tx, &source)
+}
+
+// preserveSubmission 保留被合并队伍的作品
+// 保留队伍没有作品时直接转移作品；否则将其作为一条修改记录写入保留队伍作品的历史中
+func (s *TeamMergeService) preserveSubmission(tx *gorm.DB, source, target *models.Team) error {
+	var absorbed models.Submission
+	if err := tx.Where("hackathon_id = ? AND team_id = ?", source.HackathonID, source.ID).First(&absorbed).Error; err != nil {
+		return nil // 被合并队伍没有作品
+	}
+
+	var surviving models.Submission
+	if err := tx.Where("hackathon_id = ? AND team_id = ?", target.HackathonID, target.ID).First(&surviving).Error; err != nil {
+		return tx.Model(&absorbed).Update("team_id", target.ID).Error
+	}
+
+	history, err := buildSubmissionHistory(tx, &absorbed, source.LeaderID)
+	if err != nil {
+		return err
+	}
+	history.SubmissionID = surviving.ID
+	if err := tx.Create(history).Error; err != nil {
+		return fmt.Errorf("保存作品记录失败: %w", err)
+	}
+
+	// 原作品的修改记录一并转入
+	if err := tx.Model(&models.SubmissionHistory{}).Where("submission_id = ?", absorbed.ID).
+		Update("submission_id", surviving.ID).Error; err != nil {
+		return fmt.Errorf("转移作品修改记录失败: %w", err)
+	}
+
+	// 原作品的附件转入后标记为已删除，仅可通过修改记录下载
+	if err := tx.Unscoped().Model(&models.SubmissionAttachment{}).Where("submission_id = ?", absorbed.ID).
+		Updates(map[string]interface{}{"submission_id": surviving.ID, "deleted_at": gorm.Expr("COALESCE(deleted_at, NOW())")}).Error; err != nil {
+		return fmt.Errorf("转移作品附件失败: %w", err)
+	}
+
+	// 原作品的评论转入保留队伍的作品
+	if err := tx.Unscoped().Model(&models.SubmissionComment{}).Where("submission_id = ?", absorbed.ID).
+		Update("submission_id", surviving.ID).Error; err != nil {
+		return fmt.Errorf("转移作品评论失败: %w", err)
+	}
+
+	// 原作品的链接检查记录不再需要
+	if err := tx.Where("submission_id = ?", absorbed.ID).Delete(&models.SubmissionLinkCheck{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Delete(&absorbed).Error
+}
+
+// checkMergeable 检查两支队伍当前是否可以合并
+func (s *TeamMergeService) checkMergeable(tx *gorm.DB, source, target *models.Team) error {
+	var hackathon models.Hackathon
+	if err := tx.Where("id = ? AND deleted_at IS NULL", target.HackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+
+	if hackathon.Status != "team_formation" {
+		return errors.New("当前不在组队阶段，无法合并队伍")
+	}
+
+	if source.Status == "locked" || target.Status == "locked" {
+		return errors.New("已锁定的队伍不能合并")
+	}
+
+	var sourceCount, targetCount int64
+	tx.Model(&models.TeamMember{}).Where("team_id = ?", source.ID).Count(&sourceCount)
+	tx.Model(&models.TeamMember{}).Where("team_id = ?", target.ID).Count(&targetCount)
+
+	// 合并后人数不能超过活动允许的最大人数
+	teamService := &TeamService{}
+	if _, maxSize := teamService.TeamSizeBounds(target, &hackathon); maxSize > 0 && int(sourceCount+targetCount) > maxSize {
+		return fmt.Errorf("合并后人数超过上限（最多%d人）", maxSize)
+	}
+
+	return nil
+}
+
+// leaderSide 判断队长属于合并申请的哪一方
+func (s *TeamMergeService) leaderSide(request *models.TeamMergeRequest, leaderID uint64) (string, error) {
+	var team models.Team
+	if err := database.DB.Where("id IN ? AND leader_id = ? AND deleted_at IS NULL",
+		[]uint64{request.SourceTeamID, request.TargetTeamID}, leaderID).First(&team).Error; err != nil {
+		return "", errors.New("只有参与合并的队伍队长可以处理该申请")
+	}
+	if team.ID == request.SourceTeamID {
+		return "source", nil
+	}
+	return "target", nil
+}
@@ -8,6 +8,8 @@ import (
 
 	"hackathon-backend/database"
 	"hackathon-backend/models"
+
+	"gorm.io/gorm"
 )
 
 type TeamService struct{}
@@ -50,6 +52,17 @@ func (s *TeamService) CreateTeam(hackathonID, leaderID uint64, name string, maxS
 		return nil, errors.New("您已经创建了队伍")
 	}
 
+	// 校验队伍人数上限（以活动设置为准）
+	if maxSize == 0 {
+		maxSize = hackathon.MaxTeamSize
+	}
+	if hackathon.MaxTeamSize > 0 && maxSize > hackathon.MaxTeamSize {
+		return nil, fmt.Errorf("队伍人数上限不能超过%d人", hackathon.MaxTeamSize)
+	}
+	if maxSize < hackathon.MinTeamSize || maxSize < 1 {
+		return nil, fmt.Errorf("队伍人数上限不能少于%d人", max(hackathon.MinTeamSize, 1))
+	}
+
 	// 创建队伍
 	team := models.Team{
 		HackathonID: hackathonID,
@@ -59,7 +72,34 @@ func (s *TeamService) CreateTeam(hackathonID, leaderID uint64, name string, maxS
 		Status:      "recruiting",
 	}
 
-	if err := database.DB.Create(&team).Error; err != nil {
+	// 队伍、队长成员记录和关闭求组队帖子在同一事务中完成
+	var createTeamErr error
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&team).Error; err != nil {
+			createTeamErr = err
+			return err
+		}
+
+		// 创建队长成员记录
+		member := models.TeamMember{
+			TeamID:        team.ID,
+			ParticipantID: leaderID,
+			Role:          "leader",
+			JoinedAt:      time.Now(), // 设置加入时间为当前时间
+		}
+		if err := tx.Create(&member).Error; err != nil {
+			return fmt.Errorf("创建成员记录失败: %w", err)
+		}
+
+		// 关闭求组队帖子
+		boardService := &TeamBoardService{}
+		if err := boardService.CloseForParticipant(tx, hackathonID, leaderID); err != nil {
+			return fmt.Errorf("关闭求组队帖子失败: %w", err)
+		}
+		return nil
+	})
+
+	if err := createTeamErr; err != nil {
 		// 检查是否是唯一索引冲突错误
 		if strings.Contains(err.Error(), "Duplicate entry") {
 			// 检查是哪个唯一索引冲突
@@ -85,19 +125,8 @@ func (s *TeamService) CreateTeam(hackathonID, leaderID uint64, name string, maxS
 		}
 		return nil, fmt.Errorf("创建队伍失败: %w", err)
 	}
-
-	// 创建队长成员记录
-	member := models.TeamMember{
-		TeamID:        team.ID,
-		ParticipantID: leaderID,
-		Role:          "leader",
-		JoinedAt:      time.Now(), // 设置加入时间为当前时间
-	}
-
-	if err := database.DB.Create(&member).Error; err != nil {
-		// 如果创建成员失败，删除队伍
-		database.DB.Delete(&team)
-		return nil, fmt.Errorf("创建成员记录失败: %w", err)
+	if err != nil {
+		return nil, err
 	}
 
 	return &team, nil
@@ -169,10 +198,14 @@ func (s *TeamService) JoinTeam(teamID, participantID uint64) error {
 		return errors.New("您已经在其他队伍中")
 	}
 
-	// 检查队伍是否已满
+	// 检查队伍是否已满（队伍上限不能超过活动允许的最大人数）
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", team.HackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
 	var memberCount int64
 	database.DB.Model(&models.TeamMember{}).Where("team_id = ?", teamID).Count(&memberCount)
-	if int(memberCount) >= team.MaxSize {
+	if int(memberCount) >= s.TeamCapacity(&team, &hackathon) {
 		return errors.New("队伍已满")
 	}
 
@@ -190,7 +223,40 @@ func (s *TeamService) JoinTeam(teamID, participantID uint64) error {
 		JoinedAt:      time.Now(), // 设置加入时间为当前时间
 	}
 
-	return database.DB.Create(&member).Error
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&member).Error; err != nil {
+			return err
+		}
+
+		// 组队阶段结束后补足人数的队伍自动锁定
+		if hackathon.Status != "team_formation" {
+			minSize, _ := s.TeamSizeBounds(&team, &hackathon)
+			if int(memberCount)+1 >= minSize {
+				now := time.Now()
+				if err := tx.Model(&models.Team{}).Where("id = ?", teamID).Updates(map[string]interface{}{
+					"status":    "locked",
+					"locked_at": &now,
+				}).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		// 关闭求组队帖子
+		boardService := &TeamBoardService{}
+		if err := boardService.CloseForParticipant(tx, team.HackathonID, participantID); err != nil {
+			return fmt.Errorf("关闭求组队帖子失败: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// 推送队伍频道系统事件
+	channelService := &TeamChannelService{}
+	channelService.PublishSystemEvent(teamID, participantDisplayName(participantID)+" 加入了队伍")
+
+	return nil
 }
 
 // LeaveTeam 退出队伍
@@ -206,6 +272,11 @@ func (s *TeamService) LeaveTeam(teamID, participantID uint64) error {
 		return errors.New("队长不能退出，请解散队伍")
 	}
 
+	// 检查队伍是否已锁定
+	if team.Status == "locked" {
+		return errors.New("队伍已锁定，无法退出")
+	}
+
 	// 检查活动状态
 	var hackathon models.Hackathon
 	if err := database.DB.Where("id = ? AND deleted_at IS NULL", team.HackathonID).First(&hackathon).Error; err != nil {
@@ -217,7 +288,16 @@ func (s *TeamService) LeaveTeam(teamID, participantID uint64) error {
 	}
 
 	// 删除成员记录
-	return database.DB.Where("team_id = ? AND participant_id = ?", teamID, participantID).Delete(&models.TeamMember{}).Error
+	if err := database.DB.Where("team_id = ? AND participant_id = ?", teamID, participantID).Delete(&models.TeamMember{}).Error; err != nil {
+		return err
+	}
+
+	// 断开该成员的队伍频道连接并推送系统事件
+	channelService := &TeamChannelService{}
+	channelService.Disconnect(teamID, participantID)
+	channelService.PublishSystemEvent(teamID, participantDisplayName(participantID)+" 退出了队伍")
+
+	return nil
 }
 
 // RemoveMember 移除成员（仅队长）
@@ -238,6 +318,11 @@ func (s *TeamService) RemoveMember(teamID, leaderID, memberID uint64) error {
 		return errors.New("不能移除自己")
 	}
 
+	// 检查队伍是否已锁定
+	if team.Status == "locked" {
+		return errors.New("队伍已锁定，无法移除成员")
+	}
+
 	// 检查活动状态
 	var hackathon models.Hackathon
 	if err := database.DB.Where("id = ? AND deleted_at IS NULL", team.HackathonID).First(&hackathon).Error; err != nil {
@@ -249,7 +334,19 @@ func (s *TeamService) RemoveMember(teamID, leaderID, memberID uint64) error {
 	}
 
 	// 删除成员记录
-	return database.DB.Where("team_id = ? AND participant_id = ?", teamID, memberID).Delete(&models.TeamMember{}).Error
+	result := database.DB.Where("team_id = ? AND participant_id = ?", teamID, memberID).Delete(&models.TeamMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// 断开该成员的队伍频道连接并推送系统事件
+	if result.RowsAffected > 0 {
+		channelService := &TeamChannelService{}
+		channelService.Disconnect(teamID, memberID)
+		channelService.PublishSystemEvent(teamID, participantDisplayName(memberID)+" 被移出了队伍")
+	}
+
+	return nil
 }
 
 // DissolveTeam 解散队伍（仅队长）
@@ -265,6 +362,11 @@ func (s *TeamService) DissolveTeam(teamID, leaderID uint64) error {
 		return errors.New("只有队长可以解散队伍")
 	}
 
+	// 检查队伍是否已锁定
+	if team.Status == "locked" {
+		return errors.New("队伍已锁定，无法解散")
+	}
+
 	// 检查是否有其他队员（除了队长自己）
 	var memberCount int64
 	database.DB.Model(&models.TeamMember{}).Where("team_id = ? AND participant_id != ?", teamID, leaderID).Count(&memberCount)
@@ -282,13 +384,52 @@ func (s *TeamService) DissolveTeam(teamID, leaderID uint64) error {
 		return errors.New("组队阶段已结束，无法解散队伍")
 	}
 
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return s.purgeTeam(tx, &team)
+	}); err != nil {
+		return err
+	}
+
+	// 断开队伍频道连接
+	channelService := &TeamChannelService{}
+	channelService.DisconnectTeam(teamID)
+
+	return nil
+}
+
+// purgeTeam 物理删除队伍及其成员、邀请记录和频道消息（解散或合并队伍时调用）
+func (s *TeamService) purgeTeam(tx *gorm.DB, team *models.Team) error {
 	// 物理删除成员记录
-	if err := database.DB.Unscoped().Where("team_id = ?", teamID).Delete(&models.TeamMember{}).Error; err != nil {
+	if err := tx.Unscoped().Where("team_id = ?", team.ID).Delete(&models.TeamMember{}).Error; err != nil {
 		return fmt.Errorf("删除成员记录失败: %w", err)
 	}
 
+	// 删除队伍频道消息
+	if err := tx.Where("team_id = ?", team.ID).Delete(&models.TeamMessage{}).Error; err != nil {
+		return fmt.Errorf("删除频道消息失败: %w", err)
+	}
+
+	// 删除队伍发出的邀请
+	if err := tx.Where("team_id = ?", team.ID).Delete(&models.TeamInvitation{}).Error; err != nil {
+		return fmt.Errorf("删除邀请记录失败: %w", err)
+	}
+
+	// 取消涉及该队伍的待处理合并申请
+	if err := tx.Model(&models.TeamMergeRequest{}).
+		Where("status = ? AND (source_team_id = ? OR target_team_id = ?)", "pending", team.ID, team.ID).
+		Update("status", "cancelled").Error; err != nil {
+		return fmt.Errorf("取消合并申请失败: %w", err)
+	}
+
 	// 物理删除队伍（直接删除数据库数据）
-	return database.DB.Unscoped().Delete(&team).Error
+	return tx.Unscoped().Delete(team).Error
+}
+
+// IsTeamMember 检查参赛者是否是队伍成员
+func (s *TeamService) IsTeamMember(teamID, participantID uint64) bool {
+	var count int64
+	database.DB.Model(&models.TeamMember{}).Where("team_id = ? AND participant_id = ?", teamID, participantID).Count(&count)
+	return count > 0
 }
 
 // GetUserTeam 获取用户在指定活动中的队伍信息
@@ -327,6 +468,37 @@ func (s *TeamService) UpdateTeam(teamID, leaderID uint64, updates map[string]int
 		return errors.New("组队阶段已结束，无法修改队伍信息")
 	}
 
+	// 队伍状态只能通过锁定/解锁接口修改
+	if _, ok := updates["status"]; ok {
+		return errors.New("请通过锁定/解锁操作修改队伍状态")
+	}
+	delete(updates, "locked_at")
+	// 人数特批只能由主办方设置
+	delete(updates, "min_size_override")
+	delete(updates, "max_size_override")
+
+	// 如果修改人数上限，检查是否在活动允许的范围内
+	if raw, ok := updates["max_size"]; ok {
+		value, ok := raw.(float64)
+		if !ok || value != float64(int(value)) {
+			return errors.New("队伍人数上限必须是整数")
+		}
+		maxSize := int(value)
+		minSize, maxBound := s.TeamSizeBounds(&team, &hackathon)
+		if maxBound > 0 && maxSize > maxBound {
+			return fmt.Errorf("队伍人数上限不能超过%d人", maxBound)
+		}
+		if maxSize < minSize || maxSize < 1 {
+			return fmt.Errorf("队伍人数上限不能少于%d人", max(minSize, 1))
+		}
+		var memberCount int64
+		database.DB.Model(&models.TeamMember{}).Where("team_id = ?", teamID).Count(&memberCount)
+		if int64(maxSize) < memberCount {
+			return errors.New("队伍人数上限不能少于当前成员数")
+		}
+		updates["max_size"] = maxSize
+	}
+
 	// 如果修改名称，检查是否重复
 	if name, ok := updates["name"].(string); ok {
 		var existing models.Team
@@ -338,3 +510,275 @@ func (s *TeamService) UpdateTeam(teamID, leaderID uint64, updates map[string]int
 	return database.DB.Model(&models.Team{}).Where("id = ?", teamID).Updates(updates).Error
 }
 
+// LockTeam 锁定队伍（仅队长，组队阶段内）
+// 锁定后队伍不再接受加入、退出和移除成员，锁定时校验最小队伍人数
+func (s *TeamService) LockTeam(teamID, leaderID uint64) error {
+	// 获取队伍信息
+	var team models.Team
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", teamID).First(&team).Error; err != nil {
+		return errors.New("队伍不存在")
+	}
+
+	// 检查是否是队长
+	if team.LeaderID != leaderID {
+		return errors.New("只有队长可以锁定队伍")
+	}
+
+	if team.Status == "locked" {
+		return errors.New("队伍已锁定")
+	}
+
+	// 检查活动状态
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", team.HackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+
+	if hackathon.Status != "team_formation" {
+		return errors.New("组队阶段已结束，无法锁定队伍")
+	}
+
+	// 检查最小队伍人数
+	var memberCount int64
+	database.DB.Model(&models.TeamMember{}).Where("team_id = ?", teamID).Count(&memberCount)
+	minSize, _ := s.TeamSizeBounds(&team, &hackathon)
+	if int(memberCount) < minSize {
+		return fmt.Errorf("队伍人数不足，至少需要%d人才能锁定", minSize)
+	}
+
+	now := time.Now()
+	return database.DB.Model(&models.Team{}).Where("id = ?", teamID).Updates(map[string]interface{}{
+		"status":    "locked",
+		"locked_at": &now,
+	}).Error
+}
+
+// UnlockTeam 解锁队伍（仅队长，组队阶段内）
+func (s *TeamService) UnlockTeam(teamID, leaderID uint64) error {
+	// 获取队伍信息
+	var team models.Team
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", teamID).First(&team).Error; err != nil {
+		return errors.New("队伍不存在")
+	}
+
+	// 检查是否是队长
+	if team.LeaderID != leaderID {
+		return errors.New("只有队长可以解锁队伍")
+	}
+
+	if team.Status != "locked" {
+		return errors.New("队伍未锁定")
+	}
+
+	// 检查活动状态
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", team.HackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+
+	if hackathon.Status != "team_formation" {
+		return errors.New("组队阶段已结束，无法解锁队伍")
+	}
+
+	return database.DB.Model(&models.Team{}).Where("id = ?", teamID).Updates(map[string]interface{}{
+		"status":    "recruiting",
+		"locked_at": nil,
+	}).Error
+}
+
+// SetTeamStatusByOrganizer 主办方强制锁定/解锁队伍（仅活动创建者）
+// 主办方操作不受活动阶段和最小队伍人数限制
+func (s *TeamService) SetTeamStatusByOrganizer(hackathonID, teamID, userID uint64, userRole string, status string) error {
+	if status != "locked" && status != "recruiting" {
+		return errors.New("无效的队伍状态")
+	}
+
+	// Admin不能管理队伍
+	if userRole == "admin" {
+		return errors.New("Admin不能管理队伍")
+	}
+
+	// 检查是否是活动创建者
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+	if hackathon.OrganizerID != userID {
+		return errors.New("只能管理自己创建的活动中的队伍")
+	}
+
+	var team models.Team
+	if err := database.DB.Where("id = ? AND hackathon_id = ? AND deleted_at IS NULL", teamID, hackathonID).First(&team).Error; err != nil {
+		return errors.New("队伍不存在")
+	}
+
+	updates := map[string]interface{}{"status": status}
+	if status == "locked" {
+		now := time.Now()
+		updates["locked_at"] = &now
+	} else {
+		updates["locked_at"] = nil
+	}
+
+	return database.DB.Model(&models.Team{}).Where("id = ?", teamID).Updates(updates).Error
+}
+
+// LockHackathonTeams 锁定活动中所有招募中的队伍（组队阶段结束时调用）
+// 人数未达到最小要求的队伍不锁定，保持招募中以便继续补充成员，返回这些队伍供主办方跟进
+func (s *TeamService) LockHackathonTeams(tx *gorm.DB, hackathon *models.Hackathon) ([]models.Team, error) {
+	var teams []models.Team
+	if err := tx.Where("hackathon_id = ? AND status = ? AND deleted_at IS NULL", hackathon.ID, "recruiting").
+		Find(&teams).Error; err != nil {
+		return nil, err
+	}
+	if len(teams) == 0 {
+		return nil, nil
+	}
+
+	teamIDs := make([]uint64, 0, len(teams))
+	for _, team := range teams {
+		teamIDs = append(teamIDs, team.ID)
+	}
+	var rows []struct {
+		TeamID uint64
+		Count  int
+	}
+	if err := tx.Model(&models.TeamMember{}).Select("team_id, COUNT(*) AS count").
+		Where("team_id IN ?", teamIDs).Group("team_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	memberCounts := make(map[uint64]int, len(rows))
+	for _, row := range rows {
+		memberCounts[row.TeamID] = row.Count
+	}
+
+	lockIDs, underSize := s.splitTeamsByMinSize(teams, memberCounts, hackathon)
+	if len(lockIDs) > 0 {
+		now := time.Now()
+		if err := tx.Model(&models.Team{}).Where("id IN ?", lockIDs).Updates(map[string]interface{}{
+			"status":    "locked",
+			"locked_at": &now,
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+	return underSize, nil
+}
+
+// splitTeamsByMinSize 按最小人数要求划分需要锁定的队伍和人数不足的队伍
+func (s *TeamService) splitTeamsByMinSize(teams []models.Team, memberCounts map[uint64]int, hackathon *models.Hackathon) ([]uint64, []models.Team) {
+	var lockIDs []uint64
+	var underSize []models.Team
+	for _, team := range teams {
+		minSize, _ := s.TeamSizeBounds(&team, hackathon)
+		if memberCounts[team.ID] < minSize {
+			underSize = append(underSize, team)
+			continue
+		}
+		lockIDs = append(lockIDs, team.ID)
+	}
+	return lockIDs, underSize
+}
+
+// TeamSizeBounds 获取队伍的人数范围（活动设置，主办方特批优先）
+// 返回的最大人数为0表示不限制
+func (s *TeamService) TeamSizeBounds(team *models.Team, hackathon *models.Hackathon) (int, int) {
+	minSize, maxSize := hackathon.MinTeamSize, hackathon.MaxTeamSize
+	if team.MinSizeOverride != nil {
+		minSize = *team.MinSizeOverride
+	}
+	if team.MaxSizeOverride != nil {
+		maxSize = *team.MaxSizeOverride
+	}
+	return minSize, maxSize
+}
+
+// TeamCapacity 获取队伍实际可容纳人数（队伍上限不能超过活动允许的最大人数）
+func (s *TeamService) TeamCapacity(team *models.Team, hackathon *models.Hackathon) int {
+	_, maxSize := s.TeamSizeBounds(team, hackathon)
+	if maxSize > 0 && team.MaxSize > maxSize {
+		return maxSize
+	}
+	return team.MaxSize
+}
+
+// CheckMinTeamSize 检查队伍是否达到最小人数要求
+func (s *TeamService) CheckMinTeamSize(team *models.Team, hackathon *models.Hackathon) error {
+	var memberCount int64
+	if err := database.DB.Model(&models.TeamMember{}).Where("team_id = ?", team.ID).Count(&memberCount).Error; err != nil {
+		return err
+	}
+	minSize, _ := s.TeamSizeBounds(team, hackathon)
+	if int(memberCount) < minSize {
+		return fmt.Errorf("队伍人数不足，至少需要%d人", minSize)
+	}
+	return nil
+}
+
+// SetTeamSizeOverride 主办方设置队伍人数特批（仅活动创建者）
+// minSize、maxSize 为空表示取消对应的特批，恢复使用活动设置
+func (s *TeamService) SetTeamSizeOverride(hackathonID, teamID, userID uint64, userRole string, minSize, maxSize *int) error {
+	// Admin不能管理队伍
+	if userRole == "admin" {
+		return errors.New("Admin不能管理队伍")
+	}
+
+	// 检查是否是活动创建者
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+	if hackathon.OrganizerID != userID {
+		return errors.New("只能管理自己创建的活动中的队伍")
+	}
+
+	var team models.Team
+	if err := database.DB.Where("id = ? AND hackathon_id = ? AND deleted_at IS NULL", teamID, hackathonID).First(&team).Error; err != nil {
+		return errors.New("队伍不存在")
+	}
+
+	if minSize != nil && *minSize < 1 {
+		return errors.New("最小人数不能少于1人")
+	}
+	if maxSize != nil && *maxSize < 1 {
+		return errors.New("最大人数不能少于1人")
+	}
+
+	var memberCount int64
+	if err := database.DB.Model(&models.TeamMember{}).Where("team_id = ?", teamID).Count(&memberCount).Error; err != nil {
+		return err
+	}
+
+	previousMaxOverride := team.MaxSizeOverride
+	team.MinSizeOverride = minSize
+	team.MaxSizeOverride = maxSize
+	if err := s.checkTeamSizeOverride(&team, &hackathon, int(memberCount)); err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{
+		"min_size_override": minSize,
+		"max_size_override": maxSize,
+	}
+	if maxSize != nil && *maxSize > team.MaxSize {
+		// 特批人数上限大于队伍当前上限时，同步放宽队伍上限
+		updates["max_size"] = *maxSize
+	} else if maxSize == nil && previousMaxOverride != nil && hackathon.MaxTeamSize > 0 && team.MaxSize > hackathon.MaxTeamSize {
+		// 取消人数上限特批时，因特批放宽的队伍上限恢复为活动允许的最大人数
+		updates["max_size"] = hackathon.MaxTeamSize
+	}
+
+	return database.DB.Model(&models.Team{}).Where("id = ?", teamID).Updates(updates).Error
+}
+
+// checkTeamSizeOverride 校验特批后的人数范围（team 中为新的特批设置）：最小人数不能大于最大人数，最大人数不能少于当前成员数
+func (s *TeamService) checkTeamSizeOverride(team *models.Team, hackathon *models.Hackathon, memberCount int) error {
+	minSize, maxSize := s.TeamSizeBounds(team, hackathon)
+	if maxSize > 0 && minSize > maxSize {
+		return fmt.Errorf("最小人数不能大于最大人数（%d人）", maxSize)
+	}
+	if maxSize > 0 && memberCount > maxSize {
+		return fmt.Errorf("最大人数不能少于队伍当前成员数（%d人）", memberCount)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+
+	"hackathon-backend/models"
+)
+
+func TestCheckTeamSizeOverride(t *testing.T) {
+	hackathon := &models.Hackathon{MinTeamSize: 2, MaxTeamSize: 4}
+	size := func(n int) *int { return &n }
+
+	cases := []struct {
+		name        string
+		minOverride *int
+		maxOverride *int
+		members     int
+		ok          bool
+	}{
+		{"放宽上限", nil, size(6), 5, true},
+		{"上限少于当前成员数", nil, size(3), 4, false},
+		{"只设置最小人数且超过活动上限", size(5), nil, 2, false},
+		{"只设置最小人数", size(1), nil, 1, true},
+		{"取消特批后成员数超过活动上限", nil, nil, 5, false},
+		{"取消特批", nil, nil, 4, true},
+	}
+	service := &TeamService{}
+	for _, c := range cases {
+		team := &models.Team{MinSizeOverride: c.minOverride, MaxSizeOverride: c.maxOverride}
+		err := service.checkTeamSizeOverride(team, hackathon, c.members)
+		if (err == nil) != c.ok {
+			t.Errorf("%s: 错误为 %v，期望通过=%v", c.name, err, c.ok)
+		}
+	}
+
+	// 活动不限制人数时只校验特批设置
+	unlimited := &models.Hackathon{MinTeamSize: 1}
+	if err := service.checkTeamSizeOverride(&models.Team{MinSizeOverride: size(8)}, unlimited, 10); err != nil {
+		t.Errorf("活动不限制人数时不应报错: %v", err)
+	}
+}
+
+func TestSplitTeamsByMinSize(t *testing.T) {
+	hackathon := &models.Hackathon{MinTeamSize: 3, MaxTeamSize: 5}
+	relaxed := 1
+	teams := []models.Team{
+		{ID: 1},
+		{ID: 2},
+		{ID: 3, MinSizeOverride: &relaxed},
+		{ID: 4},
+	}
+	memberCounts := map[uint64]int{1: 3, 2: 2, 3: 1}
+
+	service := &TeamService{}
+	lockIDs, underSize := service.splitTeamsByMinSize(teams, memberCounts, hackathon)
+	if len(lockIDs) != 2 || lockIDs[0] != 1 || lockIDs[1] != 3 {
+		t.Errorf("锁定的队伍为 %v，期望 [1 3]", lockIDs)
+	}
+	if len(underSize) != 2 || underSize[0].ID != 2 || underSize[1].ID != 4 {
+		t.Fatalf("人数不足的队伍数量为 %d，期望队伍2和4", len(underSize))
+	}
+}
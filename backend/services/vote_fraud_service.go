@@ -0,0 +1,321 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// fraudNewWalletWindow 钱包在投票开始前该时间内（或投票开始后）注册视为新钱包
+	fraudNewWalletWindow = 24 * time.Hour
+	// fraudClusterSize 同一作品的新钱包投票人、或使用相同客户端信息的投票人达到该数量时标记为可疑
+	fraudClusterSize = 3
+)
+
+type VoteFraudService struct{}
+
+// fraudVoter 投票人的投票汇总
+type fraudVoter struct {
+	submissionIDs map[uint64]bool
+	fingerprints  map[string]VoteFingerprint
+	latest        VoteFingerprint
+}
+
+// AnalyzeHackathon 分析活动的投票（包括排序选票），标记可疑的投票人，返回标记数量
+// 重新分析时保留主办方已处理的标记状态，已作废的标记不会被删除
+func (s *VoteFraudService) AnalyzeHackathon(hackathonID uint64) (int, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return 0, errors.New("活动不存在")
+	}
+
+	var stage models.HackathonStage
+	if err := database.DB.Where("hackathon_id = ? AND stage = ?", hackathonID, "voting").First(&stage).Error; err != nil {
+		return 0, errors.New("活动未设置投票阶段时间")
+	}
+
+	voters := make(map[uint64]*fraudVoter)
+	addVote := func(participantID, submissionID uint64, fingerprint VoteFingerprint) {
+		voter, ok := voters[participantID]
+		if !ok {
+			voter = &fraudVoter{submissionIDs: make(map[uint64]bool), fingerprints: make(map[string]VoteFingerprint)}
+			voters[participantID] = voter
+		}
+		voter.submissionIDs[submissionID] = true
+		if fingerprint.ClientIP != "" {
+			voter.fingerprints[fingerprint.ClientIP+"|"+fingerprint.UserAgent] = fingerprint
+			voter.latest = fingerprint
+		}
+	}
+
+	var votes []models.Vote
+	if err := database.DB.Where("hackathon_id = ?", hackathonID).Order("created_at ASC").Find(&votes).Error; err != nil {
+		return 0, err
+	}
+	for _, vote := range votes {
+		addVote(vote.ParticipantID, vote.SubmissionID, VoteFingerprint{ClientIP: vote.ClientIP, UserAgent: vote.UserAgent})
+	}
+
+	var ballots []models.RankedBallot
+	if err := database.DB.Preload("Items").Where("hackathon_id = ?", hackathonID).Find(&ballots).Error; err != nil {
+		return 0, err
+	}
+	for _, ballot := range ballots {
+		for _, item := range ballot.Items {
+			addVote(ballot.ParticipantID, item.SubmissionID, VoteFingerprint{ClientIP: ballot.ClientIP, UserAgent: ballot.UserAgent})
+		}
+	}
+
+	participantIDs := make([]uint64, 0, len(voters))
+	for id := range voters {
+		participantIDs = append(participantIDs, id)
+	}
+	sort.Slice(participantIDs, func(i, j int) bool { return participantIDs[i] < participantIDs[j] })
+
+	var participants []models.Participant
+	if len(participantIDs) > 0 {
+		if err := database.DB.Unscoped().Where("id IN ?", participantIDs).Find(&participants).Error; err != nil {
+			return 0, err
+		}
+	}
+	participantsByID := make(map[uint64]models.Participant, len(participants))
+	for _, participant := range participants {
+		participantsByID[participant.ID] = participant
+	}
+
+	// 新钱包：在投票开始前 fraudNewWalletWindow 内或投票开始后注册
+	newWalletSince := stage.StartTime.Add(-fraudNewWalletWindow)
+	newWallets := make(map[uint64]bool)
+	newWalletVoters := make(map[uint64]int)
+	for _, id := range participantIDs {
+		participant, ok := participantsByID[id]
+		if !ok || participant.CreatedAt.Before(newWalletSince) {
+			continue
+		}
+		newWallets[id] = true
+		for submissionID := range voters[id].submissionIDs {
+			newWalletVoters[submissionID]++
+		}
+	}
+
+	// 相同客户端信息（IP和User-Agent）的投票人数
+	fingerprintVoters := make(map[string]int)
+	for _, id := range participantIDs {
+		for key := range voters[id].fingerprints {
+			fingerprintVoters[key]++
+		}
+	}
+
+	flags := make([]models.VoteFraudFlag, 0)
+	for _, id := range participantIDs {
+		voter := voters[id]
+		participant := participantsByID[id]
+		flag := models.VoteFraudFlag{
+			HackathonID:     hackathonID,
+			ParticipantID:   id,
+			WalletAddress:   participant.WalletAddress,
+			WalletCreatedAt: participant.CreatedAt,
+			ClientIP:        voter.latest.ClientIP,
+			UserAgent:       voter.latest.UserAgent,
+			Status:          "pending",
+		}
+		details := make([]string, 0)
+
+		if newWallets[id] {
+			flag.Reasons = append(flag.Reasons, "new_wallet")
+			details = append(details, fmt.Sprintf("钱包注册于投票开始前%d小时内或投票期间", int(fraudNewWalletWindow.Hours())))
+
+			clustered := make([]string, 0)
+			for _, submissionID := range sortedSubmissionIDs(voter.submissionIDs) {
+				if newWalletVoters[submissionID] >= fraudClusterSize {
+					clustered = append(clustered, fmt.Sprintf("作品%d（%d个新钱包）", submissionID, newWalletVoters[submissionID]))
+				}
+			}
+			if len(clustered) > 0 {
+				flag.Reasons = append(flag.Reasons, "new_wallet_cluster")
+				details = append(details, "多个新钱包为同一作品投票："+strings.Join(clustered, "、"))
+			}
+		}
+
+		// 使用多个客户端投票时取共用人数最多的一个
+		sharedKey := ""
+		for key := range voter.fingerprints {
+			count := fingerprintVoters[key]
+			if count >= fraudClusterSize && (sharedKey == "" || count > fingerprintVoters[sharedKey] ||
+				(count == fingerprintVoters[sharedKey] && key < sharedKey)) {
+				sharedKey = key
+			}
+		}
+		if sharedKey != "" {
+			fingerprint := voter.fingerprints[sharedKey]
+			flag.Reasons = append(flag.Reasons, "shared_fingerprint")
+			details = append(details, fmt.Sprintf("与其他%d位投票人使用相同的IP（%s）和User-Agent", fingerprintVoters[sharedKey]-1, fingerprint.ClientIP))
+			flag.ClientIP = fingerprint.ClientIP
+			flag.UserAgent = fingerprint.UserAgent
+		}
+
+		if len(flag.Reasons) == 0 {
+			continue
+		}
+
+		for _, submissionID := range sortedSubmissionIDs(voter.submissionIDs) {
+			flag.SubmissionIDs = append(flag.SubmissionIDs, strconv.FormatUint(submissionID, 10))
+		}
+		flag.Details = truncateRunes(strings.Join(details, "；"), 1000)
+		flags = append(flags, flag)
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []models.VoteFraudFlag
+		if err := tx.Where("hackathon_id = ?", hackathonID).Find(&existing).Error; err != nil {
+			return err
+		}
+		reviewed := make(map[uint64]models.VoteFraudFlag)
+		for _, flag := range existing {
+			if flag.Status != "pending" {
+				reviewed[flag.ParticipantID] = flag
+			}
+		}
+
+		// 已作废的标记保留，以便主办方撤销作废
+		if err := tx.Where("hackathon_id = ? AND status <> ?", hackathonID, "voided").Delete(&models.VoteFraudFlag{}).Error; err != nil {
+			return err
+		}
+
+		for i := range flags {
+			old, ok := reviewed[flags[i].ParticipantID]
+			if ok && old.Status == "voided" {
+				if err := tx.Model(&old).Updates(map[string]interface{}{
+					"wallet_address":    flags[i].WalletAddress,
+					"wallet_created_at": flags[i].WalletCreatedAt,
+					"reasons":           flags[i].Reasons,
+					"details":           flags[i].Details,
+					"client_ip":         flags[i].ClientIP,
+					"user_agent":        flags[i].UserAgent,
+					"submission_ids":    flags[i].SubmissionIDs,
+				}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if ok {
+				flags[i].Status = old.Status
+				flags[i].ReviewedBy = old.ReviewedBy
+			}
+			if err := tx.Create(&flags[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(flags), nil
+}
+
+// GetFlags 获取活动的可疑投票标记
+func (s *VoteFraudService) GetFlags(hackathonID uint64, status string) ([]models.VoteFraudFlag, error) {
+	query := database.DB.Where("hackathon_id = ?", hackathonID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var flags []models.VoteFraudFlag
+	if err := query.Order("id ASC").Find(&flags).Error; err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// ReviewFlag 主办方处理可疑投票标记（voided-作废该投票人的全部投票，dismissed-忽略，pending-恢复待处理）
+// 作废和撤销作废只能在结果公布前进行，已生成的结果草稿随之按新的计票结果更新
+func (s *VoteFraudService) ReviewFlag(hackathonID, flagID, userID uint64, status string) error {
+	if status != "voided" && status != "dismissed" && status != "pending" {
+		return errors.New("无效的标记状态")
+	}
+
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+	if hackathon.ResultsPublishedAt != nil {
+		return errors.New("结果已公布，不能再处理可疑投票")
+	}
+
+	var flag models.VoteFraudFlag
+	if err := database.DB.Where("id = ? AND hackathon_id = ?", flagID, hackathonID).First(&flag).Error; err != nil {
+		return errors.New("标记不存在")
+	}
+
+	updates := map[string]interface{}{"status": status, "reviewed_by": &userID}
+	if status == "pending" {
+		updates["reviewed_by"] = nil
+	}
+	voided := status == "voided"
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&flag).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Vote{}).Where("hackathon_id = ? AND participant_id = ?", hackathonID, flag.ParticipantID).
+			Update("voided", voided).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.RankedBallot{}).Where("hackathon_id = ? AND participant_id = ?", hackathonID, flag.ParticipantID).
+			Update("voided", voided).Error; err != nil {
+			return err
+		}
+
+		// 结果草稿中的得票数随之过期，更新草稿前不能公布
+		var draftCount int64
+		if err := tx.Model(&models.HackathonResultEntry{}).
+			Where("hackathon_id = ? AND published = ?", hackathonID, false).Count(&draftCount).Error; err != nil {
+			return err
+		}
+		if draftCount == 0 {
+			return nil
+		}
+		return tx.Model(&hackathon).UpdateColumn("results_draft_stale", true).Error
+	}); err != nil {
+		return err
+	}
+
+	// 按新的计票结果更新结果草稿，失败时草稿保持过期状态，由主办方重新生成
+	if err := (&ResultService{}).refreshDraft(&hackathon); err != nil {
+		log.Printf("更新活动 %d 的结果草稿失败: %v", hackathonID, err)
+	}
+
+	(&VoteLeaderboardService{}).Publish(hackathonID)
+	return nil
+}
+
+// isVoterVoided 投票人的投票是否已被主办方作废
+func (s *VoteFraudService) isVoterVoided(hackathonID, participantID uint64) bool {
+	var count int64
+	database.DB.Model(&models.VoteFraudFlag{}).
+		Where("hackathon_id = ? AND participant_id = ? AND status = ?", hackathonID, participantID, "voided").
+		Count(&count)
+	return count > 0
+}
+
+// sortedSubmissionIDs 按ID升序返回作品ID
+func sortedSubmissionIDs(ids map[uint64]bool) []uint64 {
+	sorted := make([]uint64, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"hackathon-backend/database"
+	"hackathon-backend/models"
+)
+
+// leaderboardThrottle 投票变化后推送排行榜的最短间隔，期间的多次变化合并为一次推送
+const leaderboardThrottle = time.Second
+
+// VoteLeaderboard 实时投票排行榜
+type VoteLeaderboard struct {
+	HackathonID uint64                 `json:"hackathon_id"`
+	VotingMode  string                 `json:"voting_mode"`
+	TotalVotes  int64                  `json:"total_votes"` // 总票数（不含作废的投票）
+	VoterCount  int64                  `json:"voter_count"` // 投票人数
+	Blind       bool                   `json:"blind"`       // 是否隐藏各作品得票数
+	Entries     []VoteLeaderboardEntry `json:"entries"`     // 按得票数降序排列，隐藏时为空
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// VoteLeaderboardEntry 排行榜中的作品
+type VoteLeaderboardEntry struct {
+	Rank           int    `json:"rank"`
+	SubmissionID   uint64 `json:"submission_id"`
+	SubmissionName string `json:"submission_name"`
+	TeamName       string `json:"team_name"`
+	VoteCount      int64  `json:"vote_count"` // 得票数（排序投票为计票得分）
+}
+
+// LeaderboardClient 排行榜订阅连接
+// Tallies 为 false 时只推送总票数和投票人数；Send 在连接断开时关闭
+type LeaderboardClient struct {
+	HackathonID uint64
+	Tallies     bool
+	Send        chan []byte
+}
+
+// leaderboardHub 保存所有订阅连接（按活动分组）
+type leaderboardHub struct {
+	mu      sync.RWMutex
+	clients map[uint64]map[*LeaderboardClient]struct{}
+	pending map[uint64]bool
+}
+
+var voteLeaderboardHub = &leaderboardHub{
+	clients: make(map[uint64]map[*LeaderboardClient]struct{}),
+	pending: make(map[uint64]bool),
+}
+
+type VoteLeaderboardService struct{}
+
+// CheckPublicAccess 检查活动是否向公众开放实时排行榜，返回公众是否可以看到各作品得票数
+func (s *VoteLeaderboardService) CheckPublicAccess(hackathonID uint64) (bool, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return false, errors.New("活动不存在")
+	}
+	if !hackathon.PublicLeaderboard {
+		return false, errors.New("本活动未开放实时排行榜")
+	}
+	if hackathon.Status != "voting" && hackathon.Status != "results" {
+		return false, errors.New("投票尚未开始")
+	}
+	return showTallies(&hackathon), nil
+}
+
+// GetLeaderboard 获取活动当前的投票排行榜
+// tallies 为 false 时不返回各作品得票数
+func (s *VoteLeaderboardService) GetLeaderboard(hackathonID uint64, tallies bool) (*VoteLeaderboard, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+
+	leaderboard, err := s.buildLeaderboard(&hackathon)
+	if err != nil {
+		return nil, err
+	}
+	if !tallies {
+		leaderboard = redactLeaderboard(leaderboard)
+	}
+	return leaderboard, nil
+}
+
+// Register 订阅活动排行榜
+func (s *VoteLeaderboardService) Register(hackathonID uint64, tallies bool) *LeaderboardClient {
+	client := &LeaderboardClient{
+		HackathonID: hackathonID,
+		Tallies:     tallies,
+		Send:        make(chan []byte, 8),
+	}
+
+	voteLeaderboardHub.mu.Lock()
+	if voteLeaderboardHub.clients[hackathonID] == nil {
+		voteLeaderboardHub.clients[hackathonID] = make(map[*LeaderboardClient]struct{})
+	}
+	voteLeaderboardHub.clients[hackathonID][client] = struct{}{}
+	voteLeaderboardHub.mu.Unlock()
+
+	return client
+}
+
+// Unregister 取消订阅并关闭发送通道
+func (s *VoteLeaderboardService) Unregister(client *LeaderboardClient) {
+	voteLeaderboardHub.mu.Lock()
+	defer voteLeaderboardHub.mu.Unlock()
+
+	clients := voteLeaderboardHub.clients[client.HackathonID]
+	if _, ok := clients[client]; !ok {
+		return
+	}
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(voteLeaderboardHub.clients, client.HackathonID)
+	}
+	close(client.Send)
+}
+
+// Publish 通知活动投票发生变化（投票、取消投票、作废投票后调用）
+// 没有订阅者时直接返回；推送在后台合并进行，不影响主流程
+func (s *VoteLeaderboardService) Publish(hackathonID uint64) {
+	voteLeaderboardHub.mu.Lock()
+	if len(voteLeaderboardHub.clients[hackathonID]) == 0 || voteLeaderboardHub.pending[hackathonID] {
+		voteLeaderboardHub.mu.Unlock()
+		return
+	}
+	voteLeaderboardHub.pending[hackathonID] = true
+	voteLeaderboardHub.mu.Unlock()
+
+	go func() {
+		time.Sleep(leaderboardThrottle)
+
+		voteLeaderboardHub.mu.Lock()
+		delete(voteLeaderboardHub.pending, hackathonID)
+		voteLeaderboardHub.mu.Unlock()
+
+		s.broadcast(hackathonID)
+	}()
+}
+
+// broadcast 重新计算排行榜并推送给所有订阅连接，发送缓冲已满的连接跳过本次推送
+func (s *VoteLeaderboardService) broadcast(hackathonID uint64) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return
+	}
+
+	leaderboard, err := s.buildLeaderboard(&hackathon)
+	if err != nil {
+		log.Printf("计算投票排行榜失败: hackathon_id=%d, err=%v", hackathonID, err)
+		return
+	}
+	full, err := json.Marshal(leaderboard)
+	if err != nil {
+		log.Printf("序列化投票排行榜失败: %v", err)
+		return
+	}
+	redacted, _ := json.Marshal(redactLeaderboard(leaderboard))
+
+	// 公众订阅者是否可以看到得票数可能随活动设置和结果公布而变化
+	publicTallies := showTallies(&hackathon)
+
+	voteLeaderboardHub.mu.RLock()
+	defer voteLeaderboardHub.mu.RUnlock()
+	for client := range voteLeaderboardHub.clients[hackathonID] {
+		payload := redacted
+		if client.Tallies || publicTallies {
+			payload = full
+		}
+		select {
+		case client.Send <- payload:
+		default:
+		}
+	}
+}
+
+// buildLeaderboard 计算排行榜（作品得票数与比赛结果使用相同的计票方式）
+func (s *VoteLeaderboardService) buildLeaderboard(hackathon *models.Hackathon) (*VoteLeaderboard, error) {
+	var submissions []models.Submission
+	if err := database.DB.Preload("Team").
+		Where("hackathon_id = ? AND draft = 0 AND moderation_status IN ?", hackathon.ID, PublicModerationStatuses).
+		Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+
+	submissionIDs := make([]uint64, 0, len(submissions))
+	for _, submission := range submissions {
+		submissionIDs = append(submissionIDs, submission.ID)
+	}
+
+	// 排序投票按计票结果的名次排列（即时决选的名次不一定与最后所在轮次的票数一致）
+	counts := make(map[uint64]int64, len(submissionIDs))
+	order := make(map[uint64]int, len(submissionIDs))
+	if hackathon.VotingMode == "ranked" {
+		tallies, err := (&RankedBallotService{}).Tally(hackathon, submissionIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i, tally := range tallies {
+			counts[tally.SubmissionID] = tally.Score
+			order[tally.SubmissionID] = i
+		}
+	} else {
+		var err error
+		if counts, _, err = (&ResultService{}).voteScores(hackathon, submissionIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	leaderboard := &VoteLeaderboard{
+		HackathonID: hackathon.ID,
+		VotingMode:  hackathon.VotingMode,
+		Blind:       hackathon.BlindVoting,
+		Entries:     make([]VoteLeaderboardEntry, 0, len(submissions)),
+		UpdatedAt:   time.Now(),
+	}
+
+	if hackathon.VotingMode == "ranked" {
+		database.DB.Model(&models.RankedBallot{}).Where("hackathon_id = ? AND voided = ?", hackathon.ID, false).
+			Count(&leaderboard.TotalVotes)
+		leaderboard.VoterCount = leaderboard.TotalVotes
+	} else {
+		leaderboard.TotalVotes = (&VoteService{}).GetHackathonVoteCount(hackathon.ID)
+		database.DB.Model(&models.Vote{}).Where("hackathon_id = ? AND voided = ?", hackathon.ID, false).
+			Distinct("participant_id").Count(&leaderboard.VoterCount)
+	}
+
+	for _, submission := range submissions {
+		leaderboard.Entries = append(leaderboard.Entries, VoteLeaderboardEntry{
+			SubmissionID:   submission.ID,
+			SubmissionName: submission.Name,
+			TeamName:       submission.Team.Name,
+			VoteCount:      counts[submission.ID],
+		})
+	}
+	sort.SliceStable(leaderboard.Entries, func(i, j int) bool {
+		if hackathon.VotingMode == "ranked" {
+			return order[leaderboard.Entries[i].SubmissionID] < order[leaderboard.Entries[j].SubmissionID]
+		}
+		if leaderboard.Entries[i].VoteCount != leaderboard.Entries[j].VoteCount {
+			return leaderboard.Entries[i].VoteCount > leaderboard.Entries[j].VoteCount
+		}
+		return leaderboard.Entries[i].SubmissionID < leaderboard.Entries[j].SubmissionID
+	})
+	for i := range leaderboard.Entries {
+		leaderboard.Entries[i].Rank = i + 1
+	}
+
+	return leaderboard, nil
+}
+
+// showTallies 公众是否可以看到各作品得票数：未开启盲投，或结果已公布
+func showTallies(hackathon *models.Hackathon) bool {
+	return !hackathon.BlindVoting || hackathon.ResultsPublishedAt != nil
+}
+
+// redactLeaderboard 去掉各作品得票数，只保留总票数和投票人数
+func redactLeaderboard(leaderboard *VoteLeaderboard) *VoteLeaderboard {
+	redacted := *leaderboard
+	redacted.Entries = []VoteLeaderboardEntry{}
+	return &redacted
+}
@@ -2,28 +2,226 @@ package services
 
 import (
 	"errors"
+	"fmt"
 
 	"hackathon-backend/database"
 	"hackathon-backend/models"
+
+	"gorm.io/gorm"
 )
 
+// votingRuleColumns 活动表中的投票规则字段（仅通过投票规则接口修改）
+var votingRuleColumns = []string{"voting_mode", "voice_credits", "ranked_ballot_size", "ranked_tally_method", "max_votes_per_voter", "vote_scope", "allow_self_vote", "allow_unchecked_vote", "blind_voting", "public_leaderboard"}
+
 type VoteService struct{}
 
-// Vote 投票
-func (s *VoteService) Vote(hackathonID, participantID, submissionID uint64) error {
+// VotingRules 活动投票规则
+type VotingRules struct {
+	VotingMode         string `json:"voting_mode"`          // approval 普通投票，quadratic 平方投票，ranked 排序投票
+	VoiceCredits       int    `json:"voice_credits"`        // 平方投票模式下每位参赛者的投票额度
+	RankedBallotSize   int    `json:"ranked_ballot_size"`   // 排序投票模式下每张选票最多排序的作品数
+	RankedTallyMethod  string `json:"ranked_tally_method"`  // 排序投票计票方式：borda 波达计数，irv 即时决选
+	MaxVotesPerVoter   int    `json:"max_votes_per_voter"`  // 每位投票人（或每支队伍）最多可投票数，0表示不限制
+	VoteScope          string `json:"vote_scope"`           // participant 按参赛者计票，team 同队成员共享票数
+	AllowSelfVote      bool   `json:"allow_self_vote"`      // 是否允许为自己队伍的作品投票
+	AllowUncheckedVote bool   `json:"allow_unchecked_vote"` // 是否允许已报名未签到的参赛者投票
+	BlindVoting        bool   `json:"blind_voting"`         // 结果公布前是否对参赛者隐藏各作品得票数
+	PublicLeaderboard  bool   `json:"public_leaderboard"`   // 是否向公众开放实时投票排行榜
+}
+
+// VoteAllowance 投票人的投票额度
+type VoteAllowance struct {
+	VotingRules
+	UsedVotes        int64 `json:"used_votes"`        // 已使用票数（按队伍计票时为全队合计）
+	RemainingVotes   int   `json:"remaining_votes"`   // 剩余票数，-1表示不限制
+	UsedCredits      int   `json:"used_credits"`      // 平方投票模式下已消耗的投票额度
+	RemainingCredits int   `json:"remaining_credits"` // 平方投票模式下剩余的投票额度
+}
+
+// VoteAllocation 平方投票模式下对一个作品分配的票数
+type VoteAllocation struct {
+	SubmissionID uint64 `json:"submission_id"`
+	Votes        int    `json:"votes"`
+}
+
+// VoteFingerprint 投票时的客户端信息，用于检测可疑投票
+type VoteFingerprint struct {
+	ClientIP  string
+	UserAgent string
+}
+
+// normalize 截断超出字段长度的客户端信息
+func (f VoteFingerprint) normalize() VoteFingerprint {
+	if len(f.ClientIP) > 45 {
+		f.ClientIP = f.ClientIP[:45]
+	}
+	if len(f.UserAgent) > 500 {
+		f.UserAgent = f.UserAgent[:500]
+	}
+	return f
+}
+
+// Vote 投票（普通投票模式）
+func (s *VoteService) Vote(hackathonID, participantID, submissionID uint64, fingerprint VoteFingerprint) error {
 	// 检查活动状态
 	var hackathon models.Hackathon
 	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
 		return errors.New("活动不存在")
 	}
 
+	if hackathon.VotingMode == "quadratic" {
+		return errors.New("本活动采用平方投票，请通过分配票数进行投票")
+	}
+	if hackathon.VotingMode == "ranked" {
+		return errors.New("本活动采用排序投票，请提交排序选票")
+	}
+
+	// 检查投票资格
+	if err := s.checkVoter(&hackathon, participantID); err != nil {
+		return err
+	}
+
+	// 检查作品是否存在以及是否为自己队伍的作品
+	teamService := &TeamService{}
+	team, _ := teamService.GetUserTeam(hackathonID, participantID)
+	if _, err := s.loadVoteSubmission(&hackathon, team, submissionID); err != nil {
+		return err
+	}
+
+	// 检查是否已投票
+	var existing models.Vote
+	if err := database.DB.Where("participant_id = ? AND submission_id = ?", participantID, submissionID).First(&existing).Error; err == nil {
+		return errors.New("您已经对该作品投过票了")
+	}
+
+	// 按队伍计票时，同队成员对同一作品只能投一票
+	voterIDs := s.voterParticipantIDs(&hackathon, team, participantID)
+	if len(voterIDs) > 1 {
+		if err := database.DB.Where("participant_id IN ? AND submission_id = ?", voterIDs, submissionID).First(&existing).Error; err == nil {
+			return errors.New("您的队伍已经对该作品投过票了")
+		}
+	}
+
+	// 检查投票数上限
+	if hackathon.MaxVotesPerVoter > 0 {
+		var used int64
+		database.DB.Model(&models.Vote{}).Where("hackathon_id = ? AND participant_id IN ?", hackathonID, voterIDs).Count(&used)
+		if used >= int64(hackathon.MaxVotesPerVoter) {
+			if len(voterIDs) > 1 {
+				return fmt.Errorf("您的队伍已用完全部投票数（最多%d票）", hackathon.MaxVotesPerVoter)
+			}
+			return fmt.Errorf("您已用完全部投票数（最多%d票）", hackathon.MaxVotesPerVoter)
+		}
+	}
+
+	// 创建投票记录（已被作废投票的投票人再次投票同样作废）
+	fingerprint = fingerprint.normalize()
+	vote := models.Vote{
+		HackathonID:   hackathonID,
+		ParticipantID: participantID,
+		SubmissionID:  submissionID,
+		Votes:         1,
+		Voided:        (&VoteFraudService{}).isVoterVoided(hackathonID, participantID),
+		ClientIP:      fingerprint.ClientIP,
+		UserAgent:     fingerprint.UserAgent,
+	}
+
+	if err := database.DB.Create(&vote).Error; err != nil {
+		return err
+	}
+
+	(&VoteLeaderboardService{}).Publish(hackathonID)
+	return nil
+}
+
+// AllocateVotes 平方投票模式下整体设置参赛者的票数分配
+// 对一个作品投 n 票消耗 n² 点投票额度，投票期间可重新分配，票数为0的作品视为取消投票
+func (s *VoteService) AllocateVotes(hackathonID, participantID uint64, allocations []VoteAllocation, fingerprint VoteFingerprint) error {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+
+	if hackathon.VotingMode != "quadratic" {
+		return errors.New("本活动未采用平方投票")
+	}
+
+	// 检查投票资格
+	if err := s.checkVoter(&hackathon, participantID); err != nil {
+		return err
+	}
+
+	teamService := &TeamService{}
+	team, _ := teamService.GetUserTeam(hackathonID, participantID)
+
+	// 校验分配并计算消耗的投票额度
+	fingerprint = fingerprint.normalize()
+	voided := (&VoteFraudService{}).isVoterVoided(hackathonID, participantID)
+	seen := make(map[uint64]bool)
+	credits := 0
+	votes := make([]models.Vote, 0, len(allocations))
+	for _, allocation := range allocations {
+		if seen[allocation.SubmissionID] {
+			return fmt.Errorf("作品 %d 重复分配", allocation.SubmissionID)
+		}
+		seen[allocation.SubmissionID] = true
+
+		if allocation.Votes < 0 {
+			return errors.New("票数不能小于0")
+		}
+		if allocation.Votes == 0 {
+			continue
+		}
+
+		if _, err := s.loadVoteSubmission(&hackathon, team, allocation.SubmissionID); err != nil {
+			return fmt.Errorf("作品 %d: %w", allocation.SubmissionID, err)
+		}
+
+		credits += allocation.Votes * allocation.Votes
+		if credits > hackathon.VoiceCredits {
+			return fmt.Errorf("投票额度不足（共%d点，对一个作品投n票消耗n²点）", hackathon.VoiceCredits)
+		}
+
+		votes = append(votes, models.Vote{
+			HackathonID:   hackathonID,
+			ParticipantID: participantID,
+			SubmissionID:  allocation.SubmissionID,
+			Votes:         allocation.Votes,
+			Voided:        voided,
+			ClientIP:      fingerprint.ClientIP,
+			UserAgent:     fingerprint.UserAgent,
+		})
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("hackathon_id = ? AND participant_id = ?", hackathonID, participantID).Delete(&models.Vote{}).Error; err != nil {
+			return err
+		}
+
+		for i := range votes {
+			if err := tx.Create(&votes[i]).Error; err != nil {
+				return fmt.Errorf("保存投票失败: %w", err)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	(&VoteLeaderboardService{}).Publish(hackathonID)
+	return nil
+}
+
+// checkVoter 检查投票阶段和参赛者的投票资格
+func (s *VoteService) checkVoter(hackathon *models.Hackathon, participantID uint64) error {
 	if hackathon.Status != "voting" {
 		return errors.New("当前不在投票阶段")
 	}
 
 	// 检查阶段时间
 	hackathonService := &HackathonService{}
-	inTime, err := hackathonService.CheckStageTime(hackathonID, "voting")
+	inTime, err := hackathonService.CheckStageTime(hackathon.ID, "voting")
 	if err != nil {
 		return errors.New("投票阶段时间未设置")
 	}
@@ -31,36 +229,41 @@ func (s *VoteService) Vote(hackathonID, participantID, submissionID uint64) erro
 		return errors.New("不在投票时间范围内")
 	}
 
-	// 检查是否已签到
+	// 检查是否已签到（活动允许未签到投票时只需已报名）
 	registrationService := &RegistrationService{}
-	checkedIn, _, err := registrationService.GetCheckinStatus(hackathonID, participantID)
+	if hackathon.AllowUncheckedVote {
+		registered, _, err := registrationService.GetRegistrationStatus(hackathon.ID, participantID)
+		if err != nil {
+			return err
+		}
+		if !registered {
+			return errors.New("请先报名该活动")
+		}
+		return nil
+	}
+
+	checkedIn, _, err := registrationService.GetCheckinStatus(hackathon.ID, participantID)
 	if err != nil {
 		return err
 	}
 	if !checkedIn {
 		return errors.New("请先完成签到")
 	}
+	return nil
+}
 
-	// 检查作品是否存在
+// loadVoteSubmission 获取可投票的作品，并检查是否为投票人自己队伍的作品
+func (s *VoteService) loadVoteSubmission(hackathon *models.Hackathon, team *models.Team, submissionID uint64) (*models.Submission, error) {
 	var submission models.Submission
-	if err := database.DB.Where("id = ? AND hackathon_id = ? AND draft = 0", submissionID, hackathonID).First(&submission).Error; err != nil {
-		return errors.New("作品不存在")
+	if err := database.DB.Where("id = ? AND hackathon_id = ? AND draft = 0 AND moderation_status IN ?", submissionID, hackathon.ID, PublicModerationStatuses).First(&submission).Error; err != nil {
+		return nil, errors.New("作品不存在")
 	}
 
-	// 检查是否已投票
-	var existing models.Vote
-	if err := database.DB.Where("participant_id = ? AND submission_id = ?", participantID, submissionID).First(&existing).Error; err == nil {
-		return errors.New("您已经对该作品投过票了")
+	if !hackathon.AllowSelfVote && team != nil && submission.TeamID == team.ID {
+		return nil, errors.New("不能为自己队伍的作品投票")
 	}
 
-	// 创建投票记录
-	vote := models.Vote{
-		HackathonID:   hackathonID,
-		ParticipantID: participantID,
-		SubmissionID:  submissionID,
-	}
-
-	return database.DB.Create(&vote).Error
+	return &submission, nil
 }
 
 // CancelVote 取消投票
@@ -91,30 +294,208 @@ func (s *VoteService) CancelVote(participantID, submissionID uint64) error {
 	}
 
 	// 删除投票记录
-	return database.DB.Where("participant_id = ? AND submission_id = ?", participantID, submissionID).Delete(&models.Vote{}).Error
+	if err := database.DB.Where("participant_id = ? AND submission_id = ?", participantID, submissionID).Delete(&models.Vote{}).Error; err != nil {
+		return err
+	}
+
+	(&VoteLeaderboardService{}).Publish(vote.HackathonID)
+	return nil
 }
 
-// GetMyVotes 获取我的投票记录
-func (s *VoteService) GetMyVotes(hackathonID, participantID uint64) ([]models.Vote, error) {
+// GetMyVotes 获取我的投票记录及剩余投票额度
+// 按队伍计票时返回全队成员的投票记录
+func (s *VoteService) GetMyVotes(hackathonID, participantID uint64) ([]models.Vote, *VoteAllowance, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, nil, errors.New("活动不存在")
+	}
+
+	teamService := &TeamService{}
+	team, _ := teamService.GetUserTeam(hackathonID, participantID)
+	voterIDs := s.voterParticipantIDs(&hackathon, team, participantID)
+
 	var votes []models.Vote
 	if err := database.DB.Preload("Submission").Preload("Submission.Team").
-		Where("hackathon_id = ? AND participant_id = ?", hackathonID, participantID).
+		Where("hackathon_id = ? AND participant_id IN ?", hackathonID, voterIDs).
 		Find(&votes).Error; err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	allowance := &VoteAllowance{
+		VotingRules:    votingRulesOf(&hackathon),
+		RemainingVotes: -1,
+	}
+	if hackathon.VotingMode == "quadratic" {
+		// 平方投票：票数不设上限，受投票额度限制
+		for _, vote := range votes {
+			allowance.UsedVotes += int64(vote.Votes)
+			allowance.UsedCredits += vote.Votes * vote.Votes
+		}
+		allowance.RemainingCredits = hackathon.VoiceCredits - allowance.UsedCredits
+		if allowance.RemainingCredits < 0 {
+			allowance.RemainingCredits = 0
+		}
+	} else {
+		allowance.UsedVotes = int64(len(votes))
+		if hackathon.MaxVotesPerVoter > 0 {
+			allowance.RemainingVotes = hackathon.MaxVotesPerVoter - len(votes)
+			if allowance.RemainingVotes < 0 {
+				allowance.RemainingVotes = 0
+			}
+		}
+	}
+
+	return votes, allowance, nil
+}
+
+// GetVotingRules 获取活动投票规则
+func (s *VoteService) GetVotingRules(hackathonID uint64) (*VotingRules, error) {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return nil, errors.New("活动不存在")
+	}
+
+	rules := votingRulesOf(&hackathon)
+	return &rules, nil
+}
+
+// UpdateVotingRules 更新活动投票规则（仅活动创建者，投票开始前）
+func (s *VoteService) UpdateVotingRules(hackathonID uint64, rules VotingRules, userID uint64, userRole string) error {
+	var hackathon models.Hackathon
+	if err := database.DB.Where("id = ? AND deleted_at IS NULL", hackathonID).First(&hackathon).Error; err != nil {
+		return errors.New("活动不存在")
+	}
+
+	if userRole == "admin" {
+		return errors.New("Admin不能设置投票规则")
+	}
+	if hackathon.OrganizerID != userID {
+		return errors.New("只能设置自己创建的活动投票规则")
+	}
+	if hackathon.Status == "voting" || hackathon.Status == "results" {
+		return errors.New("投票开始后不能修改投票规则")
+	}
+
+	if rules.VotingMode == "" {
+		rules.VotingMode = "approval"
+	}
+	if rules.VoteScope == "" {
+		rules.VoteScope = "participant"
+	}
+	if rules.VoiceCredits == 0 {
+		rules.VoiceCredits = hackathon.VoiceCredits
+	}
+	if rules.RankedBallotSize == 0 {
+		rules.RankedBallotSize = hackathon.RankedBallotSize
+	}
+	if rules.RankedTallyMethod == "" {
+		rules.RankedTallyMethod = hackathon.RankedTallyMethod
+	}
+	if err := validateVotingRules(rules); err != nil {
+		return err
+	}
+
+	return database.DB.Model(&hackathon).Updates(map[string]interface{}{
+		"voting_mode":          rules.VotingMode,
+		"voice_credits":        rules.VoiceCredits,
+		"ranked_ballot_size":   rules.RankedBallotSize,
+		"ranked_tally_method":  rules.RankedTallyMethod,
+		"max_votes_per_voter":  rules.MaxVotesPerVoter,
+		"vote_scope":           rules.VoteScope,
+		"allow_self_vote":      rules.AllowSelfVote,
+		"allow_unchecked_vote": rules.AllowUncheckedVote,
+		"blind_voting":         rules.BlindVoting,
+		"public_leaderboard":   rules.PublicLeaderboard,
+	}).Error
+}
+
+// voterParticipantIDs 获取共享投票额度的参赛者：按队伍计票时为全体队员，否则仅为本人
+func (s *VoteService) voterParticipantIDs(hackathon *models.Hackathon, team *models.Team, participantID uint64) []uint64 {
+	if hackathon.VoteScope != "team" || team == nil {
+		return []uint64{participantID}
+	}
+
+	var ids []uint64
+	database.DB.Model(&models.TeamMember{}).Where("team_id = ?", team.ID).Pluck("participant_id", &ids)
+	if len(ids) == 0 {
+		return []uint64{participantID}
+	}
+	return ids
+}
+
+// votingRulesOf 提取活动的投票规则
+func votingRulesOf(hackathon *models.Hackathon) VotingRules {
+	return VotingRules{
+		VotingMode:         hackathon.VotingMode,
+		VoiceCredits:       hackathon.VoiceCredits,
+		RankedBallotSize:   hackathon.RankedBallotSize,
+		RankedTallyMethod:  hackathon.RankedTallyMethod,
+		MaxVotesPerVoter:   hackathon.MaxVotesPerVoter,
+		VoteScope:          hackathon.VoteScope,
+		AllowSelfVote:      hackathon.AllowSelfVote,
+		AllowUncheckedVote: hackathon.AllowUncheckedVote,
+		BlindVoting:        hackathon.BlindVoting,
+		PublicLeaderboard:  hackathon.PublicLeaderboard,
 	}
+}
 
-	return votes, nil
+// validateVotingRules 校验投票规则（模式和投票单位为空表示使用默认值）
+func validateVotingRules(rules VotingRules) error {
+	if rules.VotingMode != "" && rules.VotingMode != "approval" && rules.VotingMode != "quadratic" && rules.VotingMode != "ranked" {
+		return errors.New("投票模式只能是 approval、quadratic 或 ranked")
+	}
+	if rules.VoteScope != "" && rules.VoteScope != "participant" && rules.VoteScope != "team" {
+		return errors.New("投票单位只能是 participant 或 team")
+	}
+	if rules.MaxVotesPerVoter < 0 {
+		return errors.New("最多投票数不能小于0")
+	}
+	if rules.VoiceCredits < 0 {
+		return errors.New("投票额度不能小于0")
+	}
+	if rules.RankedBallotSize < 0 {
+		return errors.New("选票排序作品数不能小于0")
+	}
+	if rules.RankedTallyMethod != "" && rules.RankedTallyMethod != "borda" && rules.RankedTallyMethod != "irv" {
+		return errors.New("计票方式只能是 borda 或 irv")
+	}
+	if rules.VotingMode == "quadratic" {
+		if rules.VoteScope == "team" {
+			return errors.New("平方投票只能按参赛者计票")
+		}
+		if rules.VoiceCredits == 0 {
+			return errors.New("平方投票需要设置投票额度")
+		}
+	}
+	if rules.VotingMode == "ranked" {
+		if rules.VoteScope == "team" {
+			return errors.New("排序投票只能按参赛者计票")
+		}
+		if rules.RankedBallotSize == 0 {
+			return errors.New("排序投票需要设置每张选票排序的作品数")
+		}
+	}
+	return nil
 }
 
-// GetVoteCount 获取作品得票数
+// GetVoteCount 获取作品得票数（平方投票模式下为分配票数之和，不含作废的投票）
 func (s *VoteService) GetVoteCount(submissionID uint64) (int64, error) {
 	var count int64
-	if err := database.DB.Model(&models.Vote{}).Where("submission_id = ?", submissionID).Count(&count).Error; err != nil {
+	if err := database.DB.Model(&models.Vote{}).Where("submission_id = ? AND voided = ?", submissionID, false).
+		Select("COALESCE(SUM(votes), 0)").Scan(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
+// GetHackathonVoteCount 获取活动的总票数（不含作废的投票）
+func (s *VoteService) GetHackathonVoteCount(hackathonID uint64) int64 {
+	var count int64
+	database.DB.Model(&models.Vote{}).Where("hackathon_id = ? AND voided = ?", hackathonID, false).
+		Select("COALESCE(SUM(votes), 0)").Scan(&count)
+	return count
+}
+
 // GetResults 获取比赛结果
 func (s *VoteService) GetResults(hackathonID uint64) ([]map[string]interface{}, error) {
 	// 检查活动状态
@@ -123,66 +504,31 @@ func (s *VoteService) GetResults(hackathonID uint64) ([]map[string]interface{},
 		return nil, errors.New("活动不存在")
 	}
 
-	if hackathon.Status != "results" {
+	if hackathon.Status != "results" || hackathon.ResultsPublishedAt == nil {
 		return nil, errors.New("结果尚未公布")
 	}
 
-	// 获取所有提交的作品及其得票数
-	var submissions []models.Submission
-	if err := database.DB.Preload("Team").Preload("Team.Members").Preload("Team.Members.Participant").
-		Where("hackathon_id = ? AND draft = 0", hackathonID).Find(&submissions).Error; err != nil {
-		return nil, err
-	}
-
-	// 获取奖项设置
-	var awards []models.HackathonAward
-	if err := database.DB.Where("hackathon_id = ?", hackathonID).Order("`rank` ASC").Find(&awards).Error; err != nil {
+	// 已公布的比赛结果
+	resultService := &ResultService{}
+	entries, err := resultService.GetPublishedEntries(&hackathon)
+	if err != nil {
 		return nil, err
 	}
 
-	// 计算每个作品的得票数并排序
-	type SubmissionWithVotes struct {
-		Submission models.Submission
-		VoteCount  int64
-	}
-
-	var submissionsWithVotes []SubmissionWithVotes
-	for _, submission := range submissions {
-		voteCount, _ := s.GetVoteCount(submission.ID)
-		submissionsWithVotes = append(submissionsWithVotes, SubmissionWithVotes{
-			Submission: submission,
-			VoteCount:  voteCount,
-		})
-	}
-
-	// 按得票数排序（降序）
-	for i := 0; i < len(submissionsWithVotes)-1; i++ {
-		for j := i + 1; j < len(submissionsWithVotes); j++ {
-			if submissionsWithVotes[i].VoteCount < submissionsWithVotes[j].VoteCount {
-				submissionsWithVotes[i], submissionsWithVotes[j] = submissionsWithVotes[j], submissionsWithVotes[i]
-			}
-		}
-	}
-
 	// 构建结果
 	results := make([]map[string]interface{}, 0)
-	for rank, item := range submissionsWithVotes {
-		result := map[string]interface{}{
-			"rank":       rank + 1,
-			"team":       item.Submission.Team,
-			"submission": item.Submission,
-			"vote_count": item.VoteCount,
-			"award":      nil,
-		}
-
-		// 分配奖项
-		if rank < len(awards) {
-			result["award"] = awards[rank]
-		}
-
-		results = append(results, result)
+	for _, item := range entries {
+		results = append(results, map[string]interface{}{
+			"rank":            item.Rank,
+			"team":            item.Submission.Team,
+			"submission":      item.Submission,
+			"vote_count":      item.Breakdown.VoteCount,
+			"final_score":     item.Breakdown.FinalScore,
+			"breakdown":       item.Breakdown,
+			"award":           item.Award,
+			"special_mention": item.SpecialMention,
+		})
 	}
 
 	return results, nil
 }
-
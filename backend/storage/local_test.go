@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoragePutGetDelete(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "submissions/1/a.txt", strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+
+	r, err := store.Get(ctx, "submissions/1/a.txt")
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	got, _ := io.ReadAll(r)
+	r.Close()
+	if string(got) != "hello" {
+		t.Fatalf("读取内容 = %q", got)
+	}
+
+	if err := store.Delete(ctx, "submissions/1/a.txt"); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if _, err := store.Get(ctx, "submissions/1/a.txt"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("删除后读取应返回 ErrNotFound，实际为 %v", err)
+	}
+	if err := store.Delete(ctx, "submissions/1/a.txt"); err != nil {
+		t.Fatalf("重复删除失败: %v", err)
+	}
+}
+
+func TestLocalStorageRejectsPathTraversal(t *testing.T) {
+	root := t.TempDir()
+	baseDir := filepath.Join(root, "uploads")
+	store, err := NewLocalStorage(baseDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	outside := filepath.Join(root, "secret.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"../secret.txt", "a/../../secret.txt", "..", "", ".", "../uploads-evil/x"} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q) 应被拒绝", key)
+		}
+		if _, err := store.Get(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q) 应被拒绝，实际为 %v", key, err)
+		}
+		if err := store.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) 应被拒绝", key)
+		}
+	}
+
+	if data, _ := os.ReadFile(outside); string(data) != "secret" {
+		t.Fatalf("存储目录外的文件被修改")
+	}
+	if _, err := os.Stat(filepath.Join(root, "uploads-evil")); !os.IsNotExist(err) {
+		t.Fatalf("在存储目录外创建了目录")
+	}
+}
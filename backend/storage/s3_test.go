@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "us-east-1"
+	testBucket    = "attachments"
+)
+
+var authorizationPattern = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=([^/]+)/(\d{8})/([^/]+)/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=([0-9a-f]{64})$`)
+
+// s3Stub 模拟S3路径风格接口，校验每个请求的签名
+type s3Stub struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+	paths   []string
+}
+
+func newS3Stub(t *testing.T) (*s3Stub, *httptest.Server) {
+	stub := &s3Stub{t: t, objects: make(map[string][]byte), types: make(map[string]string)}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	return stub, server
+}
+
+func (s *s3Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := verifySignature(r); err != nil {
+		s.t.Errorf("签名校验失败: %v", err)
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paths = append(s.paths, r.URL.EscapedPath())
+
+	key := strings.TrimPrefix(r.URL.Path, "/"+testBucket+"/")
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		s.objects[key] = body
+		s.types[key] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		body, ok := s.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySignature 按 AWS Signature Version 4 独立计算签名，与请求中的签名比较
+func verifySignature(r *http.Request) error {
+	matches := authorizationPattern.FindStringSubmatch(r.Header.Get("Authorization"))
+	if matches == nil {
+		return errors.New("Authorization 格式错误: " + r.Header.Get("Authorization"))
+	}
+	accessKey, date, region, signature := matches[1], matches[2], matches[3], matches[4]
+	if accessKey != testAccessKey || region != testRegion {
+		return errors.New("凭证范围错误")
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	if !strings.HasPrefix(amzDate, date) {
+		return errors.New("X-Amz-Date 与凭证日期不一致")
+	}
+	if r.Header.Get("X-Amz-Content-Sha256") != "UNSIGNED-PAYLOAD" {
+		return errors.New("缺少 X-Amz-Content-Sha256")
+	}
+
+	path := r.RequestURI
+	query := ""
+	if i := strings.Index(path, "?"); i >= 0 {
+		path, query = path[:i], path[i+1:]
+	}
+	canonicalRequest := r.Method + "\n" + path + "\n" + query + "\n" +
+		"host:" + r.Host + "\n" +
+		"x-amz-content-sha256:UNSIGNED-PAYLOAD\n" +
+		"x-amz-date:" + amzDate + "\n\n" +
+		"host;x-amz-content-sha256;x-amz-date\n" +
+		"UNSIGNED-PAYLOAD"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + date + "/" + region + "/s3/aws4_request\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + testSecretKey)
+	for _, part := range []string{date, region, "s3", "aws4_request", stringToSign} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	if hex.EncodeToString(key) != signature {
+		return errors.New("签名不一致")
+	}
+	return nil
+}
+
+func newTestS3Storage(endpoint string) *S3Storage {
+	return NewS3Storage(S3Options{
+		Endpoint:  endpoint + "/",
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: testSecretKey,
+	})
+}
+
+func TestS3StoragePutGetDelete(t *testing.T) {
+	stub, server := newS3Stub(t)
+	store := newTestS3Storage(server.URL)
+	ctx := context.Background()
+
+	key := "submissions/1/设计 文档+v1.pdf"
+	content := []byte("hello attachment")
+	if err := store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+		t.Fatalf("上传失败: %v", err)
+	}
+	if got := stub.types[key]; got != "application/pdf" {
+		t.Fatalf("Content-Type = %q", got)
+	}
+
+	r, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("下载失败: %v", err)
+	}
+	got, _ := io.ReadAll(r)
+	r.Close()
+	if !bytes.Equal(got, content) {
+		t.Fatalf("下载内容 = %q，期望 %q", got, content)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("删除后下载应返回 ErrNotFound，实际为 %v", err)
+	}
+	// 删除不存在的对象不返回错误
+	if err := store.Delete(ctx, "missing"); err != nil {
+		t.Fatalf("删除不存在的对象失败: %v", err)
+	}
+
+	// 对象键中的非保留字符按S3规则编码
+	wantPath := "/attachments/submissions/1/%E8%AE%BE%E8%AE%A1%20%E6%96%87%E6%A1%A3%2Bv1.pdf"
+	if stub.paths[0] != wantPath {
+		t.Fatalf("请求路径 = %s，期望 %s", stub.paths[0], wantPath)
+	}
+}
+
+func TestS3StorageErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	store := newTestS3Storage(server.URL)
+	err := store.Put(context.Background(), "a.txt", strings.NewReader("x"), 1, "text/plain")
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Fatalf("期望返回S3错误信息，实际为 %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"hackathon-backend/config"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage 文件存储接口（作品附件等）
+type Storage interface {
+	// Put 写入对象，size 为 -1 表示未知大小
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 读取对象，调用方负责关闭
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+}
+
+var Default Storage
+
+// InitStorage 根据配置初始化默认存储
+func InitStorage() error {
+	cfg := config.AppConfig
+	switch cfg.StorageType {
+	case "", "local":
+		local, err := NewLocalStorage(cfg.StorageLocalDir)
+		if err != nil {
+			return err
+		}
+		Default = local
+	case "s3":
+		Default = NewS3Storage(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	default:
+		return fmt.Errorf("不支持的存储类型: %s", cfg.StorageType)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package utils
+
+import "strings"
+
+// diffMaxCells 最长公共子序列表的最大单元数，超出时不再逐行比较，整体显示为删除旧内容、插入新内容
+const diffMaxCells = 4_000_000
+
+// DiffLine 行级差异
+type DiffLine struct {
+	Op   string `json:"op"` // equal/insert/delete
+	Text string `json:"text"`
+}
+
+// DiffLines 按行比较两段文本（去掉相同的首尾行后基于最长公共子序列比较）
+func DiffLines(oldText, newText string) []DiffLine {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]DiffLine, 0, len(a)+len(b)-prefix-suffix)
+	for _, line := range a[:prefix] {
+		result = append(result, DiffLine{Op: "equal", Text: line})
+	}
+	result = append(result, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		result = append(result, DiffLine{Op: "equal", Text: line})
+	}
+	return result
+}
+
+// diffMiddle 比较去掉相同首尾行后的部分
+func diffMiddle(a, b []string) []DiffLine {
+	result := make([]DiffLine, 0, len(a)+len(b))
+	if (len(a)+1)*(len(b)+1) > diffMaxCells {
+		for _, line := range a {
+			result = append(result, DiffLine{Op: "delete", Text: line})
+		}
+		for _, line := range b {
+			result = append(result, DiffLine{Op: "insert", Text: line})
+		}
+		return result
+	}
+
+	// lcs[i][j] 表示 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, DiffLine{Op: "equal", Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, DiffLine{Op: "delete", Text: a[i]})
+			i++
+		default:
+			result = append(result, DiffLine{Op: "insert", Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, DiffLine{Op: "delete", Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		result = append(result, DiffLine{Op: "insert", Text: b[j]})
+	}
+	return result
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	got := DiffLines("a\nb\nc\nd", "a\nx\nc\nd\ne")
+	want := []DiffLine{
+		{Op: "equal", Text: "a"},
+		{Op: "delete", Text: "b"},
+		{Op: "insert", Text: "x"},
+		{Op: "equal", Text: "c"},
+		{Op: "equal", Text: "d"},
+		{Op: "insert", Text: "e"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiffLines = %v，期望 %v", got, want)
+	}
+}
+
+func TestDiffLinesLargeInputFallsBack(t *testing.T) {
+	oldLines := make([]string, 30000)
+	newLines := make([]string, 30000)
+	for i := range oldLines {
+		oldLines[i] = "old " + strconv.Itoa(i)
+		newLines[i] = "new " + strconv.Itoa(i)
+	}
+	// 相同的首尾行仍按相同显示
+	oldText := "title\n" + strings.Join(oldLines, "\n") + "\nfooter"
+	newText := "title\n" + strings.Join(newLines, "\n") + "\nfooter"
+
+	got := DiffLines(oldText, newText)
+	if len(got) != 60002 {
+		t.Fatalf("差异行数 = %d，期望 60002", len(got))
+	}
+	if got[0] != (DiffLine{Op: "equal", Text: "title"}) || got[len(got)-1] != (DiffLine{Op: "equal", Text: "footer"}) {
+		t.Fatalf("首尾相同行应为 equal")
+	}
+	if got[1].Op != "delete" || got[30001].Op != "insert" {
+		t.Fatalf("超出比较上限时应整体删除旧内容、插入新内容")
+	}
+}
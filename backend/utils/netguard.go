@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress 目标地址为内网地址
+var ErrPrivateAddress = errors.New("不允许访问内网地址")
+
+// carrierGradeNAT 运营商级NAT地址段（100.64.0.0/10）
+var carrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicIP 是否为公网地址（排除回环、内网、链路本地、组播、未指定等地址）
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil && (ip4[0] == 0 || carrierGradeNAT.Contains(ip4) || ip4.Equal(net.IPv4bcast)) {
+		return false
+	}
+	return true
+}
+
+// ResolvePublicHost 解析主机名并确认所有地址均为公网地址，返回第一个地址
+func ResolvePublicHost(ctx context.Context, host string) (net.IP, error) {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("解析域名失败: %w", err)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("解析域名失败: %s", host)
+	}
+	for _, addr := range addrs {
+		if !IsPublicIP(addr.IP) {
+			return nil, ErrPrivateAddress
+		}
+	}
+	return addrs[0].IP, nil
+}
+
+// NewPublicHTTPClient 创建只能访问公网地址的HTTP客户端
+// 在建立连接时检查实际连接的地址，重定向和DNS重绑定同样受限；allowPrivate 为 true 时不限制（仅用于本地测试）
+func NewPublicHTTPClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !IsPublicIP(net.ParseIP(host)) {
+				return ErrPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// 不使用代理，否则连接检查针对的是代理地址
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"hackathon-backend/config"
+)
+
+// SignResource 为资源生成带有效期的签名，返回过期时间戳和签名
+func SignResource(resource string, id uint64, ttl time.Duration) (int64, string) {
+	expires := time.Now().Add(ttl).Unix()
+	return expires, resourceSignature(resource, id, expires)
+}
+
+// VerifyResourceSignature 校验资源签名是否有效且未过期
+func VerifyResourceSignature(resource string, id uint64, expires int64, signature string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+	expected := resourceSignature(resource, id, expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func resourceSignature(resource string, id uint64, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(config.AppConfig.JWTSecret))
+	fmt.Fprintf(mac, "%s:%d:%d", resource, id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// TextSimilarity 计算两段文本的相似度（0~1），基于字符 n-gram 的 Jaccard 系数
+// 忽略大小写、空白和标点，适用于中英文混合文本
+func TextSimilarity(a, b string) float64 {
+	ra, rb := normalizeText(a), normalizeText(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	// 短文本（如作品名称）使用二元组，长文本使用三元组
+	n := 3
+	if len(ra) < 20 || len(rb) < 20 {
+		n = 2
+	}
+
+	sa, sb := shingles(ra, n), shingles(rb, n)
+	intersection := 0
+	for gram := range sa {
+		if sb[gram] {
+			intersection++
+		}
+	}
+	union := len(sa) + len(sb) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+// NormalizeURL 规范化链接以便比较（忽略协议、www 前缀、大小写、末尾斜杠和 .git 后缀）
+func NormalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	path := strings.TrimSuffix(strings.TrimRight(strings.ToLower(u.Path), "/"), ".git")
+	return host + path
+}
+
+func normalizeText(text string) []rune {
+	runes := make([]rune, 0, len(text))
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+func shingles(runes []rune, n int) map[string]bool {
+	set := make(map[string]bool)
+	if len(runes) <= n {
+		set[string(runes)] = true
+		return set
+	}
+	for i := 0; i+n <= len(runes); i++ {
+		set[string(runes[i:i+n])] = true
+	}
+	return set
+}
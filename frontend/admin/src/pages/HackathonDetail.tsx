@@ -140,8 +140,16 @@ export default function HackathonDetail() {
 
   const handleSwitchStage = async (stage: string) => {
     try {
-      await request.post(`/hackathons/${id}/stages/${stage}/switch`)
+      const data = (await request.post(`/hackathons/${id}/stages/${stage}/switch`)) as any
       message.success(t('hackathon.switchStageSuccess'))
+      const underSizeTeams: any[] = data?.under_size_teams || []
+      if (underSizeTeams.length > 0) {
+        message.warning(
+          t('hackathon.underSizeTeamsNotLocked', {
+            teams: underSizeTeams.map((team) => team.name).join(', '),
+          })
+        )
+      }
       fetchDetail()
     } catch (error) {
       message.error(t('hackathon.switchStageFailed'))
@@ -29,8 +29,8 @@ export default function SubmissionList() {
 
   const fetchMyVotes = async () => {
     try {
-      const votes = await request.get(`/hackathons/${id}/votes`)
-      const ids = new Set<number>(votes.map((v: any) => v.submission_id))
+      const data = await request.get(`/hackathons/${id}/votes`)
+      const ids = new Set<number>((data.votes || []).map((v: any) => v.submission_id))
       setVotedIds(ids)
     } catch (error) {
       // 忽略错误